@@ -17,20 +17,80 @@
 package main
 
 import (
+	"context"
 	"log"
+	"sync"
+	"time"
 
 	controllerv1beta1 "github.com/percona-platform/dbaas-api/gen/controller"
 	"github.com/percona/pmm/version"
+	"github.com/pkg/errors"
 	"google.golang.org/grpc/grpclog"
 	"gopkg.in/alecthomas/kingpin.v2"
+	"k8s.io/client-go/rest"
 
 	"github.com/percona-platform/dbaas-controller/service/cluster"
+	"github.com/percona-platform/dbaas-controller/service/k8sclient"
 	"github.com/percona-platform/dbaas-controller/service/logs"
 	"github.com/percona-platform/dbaas-controller/utils/app"
 	"github.com/percona-platform/dbaas-controller/utils/logger"
 	"github.com/percona-platform/dbaas-controller/utils/servers"
 )
 
+const readyzTimeout = 5 * time.Second
+
+// readinessChecker checks that Kubernetes API server is reachable.
+// In-cluster client is created on the first successful check and reused by the next ones.
+type readinessChecker struct {
+	opts []k8sclient.Option
+
+	m      sync.Mutex
+	client *k8sclient.K8sClient
+}
+
+// readyz returns nil if Kubernetes API server is reachable.
+// Check is skipped when dbaas-controller is running outside of Kubernetes cluster.
+func (r *readinessChecker) readyz(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, readyzTimeout)
+	defer cancel()
+
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	if r.client == nil {
+		client, err := k8sclient.NewIncluster(ctx, r.opts...)
+		if err != nil {
+			if errors.Is(err, rest.ErrNotInCluster) {
+				return nil
+			}
+			return errors.Wrap(err, "cannot connect to Kubernetes API server")
+		}
+		r.client = client
+	}
+
+	return r.client.CheckReadiness(ctx)
+}
+
+// cleanup releases resources of the client.
+func (r *readinessChecker) cleanup() error {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	if r.client == nil {
+		return nil
+	}
+	return r.client.Cleanup()
+}
+
+// clientOptions returns options of Kubernetes clients set by flags.
+func clientOptions(flags *app.Flags) []k8sclient.Option {
+	return []k8sclient.Option{
+		k8sclient.WithHTTPTimeout(flags.HTTPTimeout),
+		k8sclient.WithManifestFetchTimeout(flags.ManifestFetchTimeout),
+		k8sclient.WithKubeConnectionPool(k8sclient.ConnectionPool(flags.KubeConnectionPool)),
+	}
+}
+
 func main() {
 	if version.Version == "" {
 		panic("dbaas-controller version is not set during build.")
@@ -59,25 +119,29 @@ func main() {
 	grpclog.SetLoggerV2(l.GRPCLogger())
 
 	gRPCServer := servers.NewGRPCServer(ctx, &servers.NewGRPCServerOpts{
-		Addr: flags.GRPCAddr,
+		Addr:            flags.GRPCAddr,
+		ShutdownTimeout: flags.ShutdownTimeout,
 	})
 	if err != nil {
 		l.Fatalf("Failed to create gRPC server: %s.", err)
 	}
 
-	controllerv1beta1.RegisterPXCClusterAPIServer(gRPCServer.GetUnderlyingServer(), cluster.NewPXCClusterService())
-	controllerv1beta1.RegisterPSMDBClusterAPIServer(gRPCServer.GetUnderlyingServer(), cluster.NewPSMDBClusterService())
-	controllerv1beta1.RegisterKubernetesClusterAPIServer(gRPCServer.GetUnderlyingServer(), cluster.NewKubernetesClusterService())
-	controllerv1beta1.RegisterLogsAPIServer(gRPCServer.GetUnderlyingServer(), logs.NewService())
+	clientOpts := clientOptions(flags)
+	controllerv1beta1.RegisterPXCClusterAPIServer(gRPCServer.GetUnderlyingServer(), cluster.NewPXCClusterService(clientOpts...))
+	controllerv1beta1.RegisterPSMDBClusterAPIServer(gRPCServer.GetUnderlyingServer(), cluster.NewPSMDBClusterService(clientOpts...))
+	controllerv1beta1.RegisterKubernetesClusterAPIServer(gRPCServer.GetUnderlyingServer(), cluster.NewKubernetesClusterService(clientOpts...))
+	controllerv1beta1.RegisterLogsAPIServer(gRPCServer.GetUnderlyingServer(), logs.NewService(clientOpts...))
 	// controllerv1beta1.RegisterPXCOperatorAPIServer(gRPCServer.GetUnderlyingServer(), operator.NewPXCOperatorService(flags.PXCOperatorURLTemplate))
 	// controllerv1beta1.RegisterPSMDBOperatorAPIServer(gRPCServer.GetUnderlyingServer(), operator.NewPSMDBOperatorService(flags.PSMDBOperatorURLTemplate))
 	// controllerv1beta1.RegisterOLMOperatorAPIServer(gRPCServer.GetUnderlyingServer(), olm.NewOperatorService())
 
+	readiness := &readinessChecker{opts: clientOpts}
+	defer readiness.cleanup() //nolint:errcheck
+
 	go servers.RunDebugServer(ctx, &servers.RunDebugServerOpts{
 		Addr: flags.DebugAddr,
 		Readyz: func() error {
-			// TODO: add your services checks here
-			return nil
+			return readiness.readyz(ctx)
 		},
 	})
 
@@ -29,16 +29,19 @@ import (
 )
 
 // KubernetesClusterService implements methods of gRPC server and other business logic related to kubernetes clusters.
-type KubernetesClusterService struct{}
+type KubernetesClusterService struct {
+	clientOpts []k8sclient.Option
+}
 
 // NewKubernetesClusterService returns new KubernetesClusterService instance.
-func NewKubernetesClusterService() *KubernetesClusterService {
-	return new(KubernetesClusterService)
+// Given options are applied to Kubernetes clients created for requests.
+func NewKubernetesClusterService(opts ...k8sclient.Option) *KubernetesClusterService {
+	return &KubernetesClusterService{clientOpts: opts}
 }
 
 // CheckKubernetesClusterConnection checks connection with kubernetes cluster.
 func (k KubernetesClusterService) CheckKubernetesClusterConnection(ctx context.Context, req *controllerv1beta1.CheckKubernetesClusterConnectionRequest) (*controllerv1beta1.CheckKubernetesClusterConnectionResponse, error) {
-	k8Client, err := k8sclient.New(ctx, req.KubeAuth.Kubeconfig)
+	k8Client, err := k8sclient.New(ctx, req.KubeAuth.Kubeconfig, k.clientOpts...)
 	if err != nil {
 		return nil, status.Errorf(codes.FailedPrecondition, "Unable to connect to Kubernetes cluster: %s", err)
 	}
@@ -66,7 +69,7 @@ func (k KubernetesClusterService) CheckKubernetesClusterConnection(ctx context.C
 
 // GetResources returns total and available amounts of resources of certain k8s cluster.
 func (k KubernetesClusterService) GetResources(ctx context.Context, req *controllerv1beta1.GetResourcesRequest) (*controllerv1beta1.GetResourcesResponse, error) {
-	k8sClient, err := k8sclient.New(ctx, req.KubeAuth.Kubeconfig)
+	k8sClient, err := k8sclient.New(ctx, req.KubeAuth.Kubeconfig, k.clientOpts...)
 	if err != nil {
 		return nil, status.Errorf(codes.FailedPrecondition, "Unable to connect to Kubernetes cluster: %s", err)
 	}
@@ -81,12 +84,12 @@ func (k KubernetesClusterService) GetResources(ctx context.Context, req *control
 			return nil, status.Error(codes.Internal, err.Error())
 		}
 	}
-	allCPUMillis, allMemoryBytes, allDiskBytes, err := k8sClient.GetAllClusterResources(ctx, clusterType, volumes)
+	allCPUMillis, allMemoryBytes, allDiskBytes, err := k8sClient.GetAllClusterResources(ctx, clusterType, volumes, nil)
 	if err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 
-	consumedCPUMillis, consumedMemoryBytes, err := k8sClient.GetConsumedCPUAndMemory(ctx, "")
+	consumedCPUMillis, consumedMemoryBytes, err := k8sClient.GetConsumedCPUAndMemory(ctx, "", "")
 	if err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
 	}
@@ -128,7 +131,7 @@ func (k KubernetesClusterService) GetResources(ctx context.Context, req *control
 
 // StartMonitoring sets up victoria metrics operator to monitor kubernetes cluster.
 func (k KubernetesClusterService) StartMonitoring(ctx context.Context, req *controllerv1beta1.StartMonitoringRequest) (*controllerv1beta1.StartMonitoringResponse, error) {
-	k8sClient, err := k8sclient.New(ctx, req.KubeAuth.Kubeconfig)
+	k8sClient, err := k8sclient.New(ctx, req.KubeAuth.Kubeconfig, k.clientOpts...)
 	if err != nil {
 		return nil, status.Errorf(codes.FailedPrecondition, "Unable to connect to Kubernetes cluster: %s", err)
 	}
@@ -138,7 +141,7 @@ func (k KubernetesClusterService) StartMonitoring(ctx context.Context, req *cont
 		PublicAddress: req.Pmm.PublicAddress,
 		Login:         req.Pmm.Login,
 		Password:      req.Pmm.Password,
-	})
+	}, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -148,7 +151,7 @@ func (k KubernetesClusterService) StartMonitoring(ctx context.Context, req *cont
 
 // StopMonitoring removes Victoria metrics operator from kubernetes cluster.
 func (k KubernetesClusterService) StopMonitoring(ctx context.Context, req *controllerv1beta1.StopMonitoringRequest) (*controllerv1beta1.StopMonitoringResponse, error) {
-	k8sClient, err := k8sclient.New(ctx, req.KubeAuth.Kubeconfig)
+	k8sClient, err := k8sclient.New(ctx, req.KubeAuth.Kubeconfig, k.clientOpts...)
 	if err != nil {
 		return nil, status.Errorf(codes.FailedPrecondition, "Unable to connect to Kubernetes cluster: %s", err)
 	}
@@ -163,7 +166,7 @@ func (k KubernetesClusterService) StopMonitoring(ctx context.Context, req *contr
 
 // GetKubeconfig initializes incluster client and generates and returns its kubeconfig
 func (k KubernetesClusterService) GetKubeconfig(ctx context.Context, req *controllerv1beta1.GetKubeconfigRequest) (*controllerv1beta1.GetKubeconfigResponse, error) {
-	client, err := k8sclient.NewIncluster(ctx)
+	client, err := k8sclient.NewIncluster(ctx, k.clientOpts...)
 	if err != nil {
 		return nil, status.Errorf(codes.FailedPrecondition, "Unable to connect to Kubernetes cluster: %s", err)
 	}
@@ -29,16 +29,19 @@ import (
 )
 
 // PSMDBClusterService implements methods of gRPC server and other business logic related to PSMDB clusters.
-type PSMDBClusterService struct{}
+type PSMDBClusterService struct {
+	clientOpts []k8sclient.Option
+}
 
 // NewPSMDBClusterService returns new PSMDBClusterService instance.
-func NewPSMDBClusterService() *PSMDBClusterService {
-	return new(PSMDBClusterService)
+// Given options are applied to Kubernetes clients created for requests.
+func NewPSMDBClusterService(opts ...k8sclient.Option) *PSMDBClusterService {
+	return &PSMDBClusterService{clientOpts: opts}
 }
 
 // ListPSMDBClusters returns a list of PSMDB clusters.
 func (s *PSMDBClusterService) ListPSMDBClusters(ctx context.Context, req *controllerv1beta1.ListPSMDBClustersRequest) (*controllerv1beta1.ListPSMDBClustersResponse, error) {
-	client, err := k8sclient.New(ctx, req.KubeAuth.Kubeconfig)
+	client, err := k8sclient.New(ctx, req.KubeAuth.Kubeconfig, s.clientOpts...)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "Cannot initialize K8s client: %s", err)
 	}
@@ -96,7 +99,7 @@ func (s *PSMDBClusterService) ListPSMDBClusters(ctx context.Context, req *contro
 
 // CreatePSMDBCluster creates a new PSMDB cluster.
 func (s *PSMDBClusterService) CreatePSMDBCluster(ctx context.Context, req *controllerv1beta1.CreatePSMDBClusterRequest) (*controllerv1beta1.CreatePSMDBClusterResponse, error) {
-	client, err := k8sclient.New(ctx, req.KubeAuth.Kubeconfig)
+	client, err := k8sclient.New(ctx, req.KubeAuth.Kubeconfig, s.clientOpts...)
 	if err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
 	}
@@ -108,7 +111,7 @@ func (s *PSMDBClusterService) CreatePSMDBCluster(ctx context.Context, req *contr
 		BackupImage: req.Params.BackupImage,
 		Size:        req.Params.ClusterSize,
 		Replicaset: &k8sclient.Replicaset{
-			DiskSize: convertors.BytesToStr(req.Params.Replicaset.DiskSize),
+			DiskSize: convertors.BytesToStr(uint64(req.Params.Replicaset.DiskSize)),
 		},
 		Expose:            req.Expose,
 		VersionServiceURL: req.Params.VersionServiceUrl,
@@ -128,6 +131,17 @@ func (s *PSMDBClusterService) CreatePSMDBCluster(ctx context.Context, req *contr
 
 	err = client.CreatePSMDBCluster(ctx, params)
 	if err != nil {
+		if errors.Is(err, k8sclient.ErrUnsafeClusterSize) || errors.Is(err, k8sclient.ErrInvalidReplsetMembers) ||
+			errors.Is(err, k8sclient.ErrInvalidConfigServerSize) || errors.Is(err, k8sclient.ErrInvalidComputeResources) ||
+			errors.Is(err, k8sclient.ErrInvalidReplsetName) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		if errors.Is(err, k8sclient.ErrAPIVersionNotInstalled) || errors.Is(err, k8sclient.ErrResourcesExceedNodeCapacity) {
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		}
+		if errors.Is(err, k8sclient.ErrOperatorUpgrading) {
+			return nil, status.Error(codes.Unavailable, err.Error())
+		}
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 
@@ -136,7 +150,7 @@ func (s *PSMDBClusterService) CreatePSMDBCluster(ctx context.Context, req *contr
 
 // UpdatePSMDBCluster updates existing PSMDB cluster.
 func (s *PSMDBClusterService) UpdatePSMDBCluster(ctx context.Context, req *controllerv1beta1.UpdatePSMDBClusterRequest) (*controllerv1beta1.UpdatePSMDBClusterResponse, error) {
-	client, err := k8sclient.New(ctx, req.KubeAuth.Kubeconfig)
+	client, err := k8sclient.New(ctx, req.KubeAuth.Kubeconfig, s.clientOpts...)
 	if err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
 	}
@@ -176,8 +190,9 @@ func (s *PSMDBClusterService) UpdatePSMDBCluster(ctx context.Context, req *contr
 }
 
 // DeletePSMDBCluster deletes PSMDB cluster.
+// NotFound is returned if cluster doesn't exist, including repeated deletion of the same cluster.
 func (s *PSMDBClusterService) DeletePSMDBCluster(ctx context.Context, req *controllerv1beta1.DeletePSMDBClusterRequest) (*controllerv1beta1.DeletePSMDBClusterResponse, error) {
-	client, err := k8sclient.New(ctx, req.KubeAuth.Kubeconfig)
+	client, err := k8sclient.New(ctx, req.KubeAuth.Kubeconfig, s.clientOpts...)
 	if err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
 	}
@@ -185,6 +200,9 @@ func (s *PSMDBClusterService) DeletePSMDBCluster(ctx context.Context, req *contr
 
 	err = client.DeletePSMDBCluster(ctx, req.Name)
 	if err != nil {
+		if errors.Is(err, k8sclient.ErrNotFound) {
+			return nil, status.Error(codes.NotFound, err.Error())
+		}
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 	return new(controllerv1beta1.DeletePSMDBClusterResponse), nil
@@ -192,7 +210,7 @@ func (s *PSMDBClusterService) DeletePSMDBCluster(ctx context.Context, req *contr
 
 // RestartPSMDBCluster restarts PSMDB cluster.
 func (s *PSMDBClusterService) RestartPSMDBCluster(ctx context.Context, req *controllerv1beta1.RestartPSMDBClusterRequest) (*controllerv1beta1.RestartPSMDBClusterResponse, error) {
-	client, err := k8sclient.New(ctx, req.KubeAuth.Kubeconfig)
+	client, err := k8sclient.New(ctx, req.KubeAuth.Kubeconfig, s.clientOpts...)
 	if err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
 	}
@@ -207,7 +225,7 @@ func (s *PSMDBClusterService) RestartPSMDBCluster(ctx context.Context, req *cont
 
 // GetPSMDBClusterCredentials returns a PSMDB cluster connection credentials.
 func (s *PSMDBClusterService) GetPSMDBClusterCredentials(ctx context.Context, req *controllerv1beta1.GetPSMDBClusterCredentialsRequest) (*controllerv1beta1.GetPSMDBClusterCredentialsResponse, error) {
-	client, err := k8sclient.New(ctx, req.KubeAuth.Kubeconfig)
+	client, err := k8sclient.New(ctx, req.KubeAuth.Kubeconfig, s.clientOpts...)
 	if err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
 	}
@@ -44,11 +44,13 @@ var dbClusterStatesMap = map[k8sclient.ClusterState]controllerv1beta1.DBClusterS
 
 // PXCClusterService implements methods of gRPC server and other business logic related to PXC clusters.
 type PXCClusterService struct { // p *message.Printer
+	clientOpts []k8sclient.Option
 }
 
 // NewPXCClusterService returns new PXCClusterService instance.
-func NewPXCClusterService() *PXCClusterService {
-	return new(PXCClusterService)
+// Given options are applied to Kubernetes clients created for requests.
+func NewPXCClusterService(opts ...k8sclient.Option) *PXCClusterService {
+	return &PXCClusterService{clientOpts: opts}
 }
 
 // setComputeResources converts input resources and sets them to output compute resources.
@@ -73,7 +75,7 @@ func setComputeResources(inputResources *k8sclient.ComputeResources, outputResou
 
 // ListPXCClusters returns a list of PXC clusters.
 func (s *PXCClusterService) ListPXCClusters(ctx context.Context, req *controllerv1beta1.ListPXCClustersRequest) (*controllerv1beta1.ListPXCClustersResponse, error) {
-	client, err := k8sclient.New(ctx, req.KubeAuth.Kubeconfig)
+	client, err := k8sclient.New(ctx, req.KubeAuth.Kubeconfig, s.clientOpts...)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "Cannot initialize K8s client: %s", err)
 	}
@@ -150,7 +152,7 @@ func (s *PXCClusterService) ListPXCClusters(ctx context.Context, req *controller
 
 // CreatePXCCluster creates a new PXC cluster.
 func (s *PXCClusterService) CreatePXCCluster(ctx context.Context, req *controllerv1beta1.CreatePXCClusterRequest) (*controllerv1beta1.CreatePXCClusterResponse, error) {
-	client, err := k8sclient.New(ctx, req.KubeAuth.Kubeconfig)
+	client, err := k8sclient.New(ctx, req.KubeAuth.Kubeconfig, s.clientOpts...)
 	if err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
 	}
@@ -162,7 +164,7 @@ func (s *PXCClusterService) CreatePXCCluster(ctx context.Context, req *controlle
 		PXC: &k8sclient.PXC{
 			Image:            req.Params.Pxc.Image,
 			ComputeResources: computeResources(req.Params.Pxc.ComputeResources),
-			DiskSize:         convertors.BytesToStr(req.Params.Pxc.DiskSize),
+			DiskSize:         convertors.BytesToStr(uint64(req.Params.Pxc.DiskSize)),
 		},
 		Expose:            req.Expose,
 		VersionServiceURL: req.Params.VersionServiceUrl,
@@ -171,7 +173,7 @@ func (s *PXCClusterService) CreatePXCCluster(ctx context.Context, req *controlle
 		params.ProxySQL = &k8sclient.ProxySQL{
 			Image:            req.Params.Proxysql.Image,
 			ComputeResources: computeResources(req.Params.Proxysql.ComputeResources),
-			DiskSize:         convertors.BytesToStr(req.Params.Proxysql.DiskSize),
+			DiskSize:         convertors.BytesToStr(uint64(req.Params.Proxysql.DiskSize)),
 		}
 	} else {
 		params.HAProxy = &k8sclient.HAProxy{
@@ -189,6 +191,16 @@ func (s *PXCClusterService) CreatePXCCluster(ctx context.Context, req *controlle
 	}
 	err = client.CreatePXCCluster(ctx, params)
 	if err != nil {
+		if errors.Is(err, k8sclient.ErrUnsafeClusterSize) || errors.Is(err, k8sclient.ErrInvalidProxyConfig) ||
+			errors.Is(err, k8sclient.ErrInvalidComputeResources) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		if errors.Is(err, k8sclient.ErrAPIVersionNotInstalled) || errors.Is(err, k8sclient.ErrResourcesExceedNodeCapacity) {
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		}
+		if errors.Is(err, k8sclient.ErrOperatorUpgrading) {
+			return nil, status.Error(codes.Unavailable, err.Error())
+		}
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 	return new(controllerv1beta1.CreatePXCClusterResponse), nil
@@ -199,14 +211,14 @@ func computeResources(pxcRes *controllerv1beta1.ComputeResources) *k8sclient.Com
 		return nil
 	}
 	return &k8sclient.ComputeResources{
-		CPUM:        convertors.MilliCPUToStr(pxcRes.CpuM),
-		MemoryBytes: convertors.BytesToStr(pxcRes.MemoryBytes),
+		CPUM:        convertors.MilliCPUToStr(uint64(pxcRes.CpuM)),
+		MemoryBytes: convertors.BytesToStr(uint64(pxcRes.MemoryBytes)),
 	}
 }
 
 // UpdatePXCCluster updates existing PXC cluster.
 func (s *PXCClusterService) UpdatePXCCluster(ctx context.Context, req *controllerv1beta1.UpdatePXCClusterRequest) (*controllerv1beta1.UpdatePXCClusterResponse, error) {
-	client, err := k8sclient.New(ctx, req.KubeAuth.Kubeconfig)
+	client, err := k8sclient.New(ctx, req.KubeAuth.Kubeconfig, s.clientOpts...)
 	if err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
 	}
@@ -244,6 +256,9 @@ func (s *PXCClusterService) UpdatePXCCluster(ctx context.Context, req *controlle
 
 	err = client.UpdatePXCCluster(ctx, params)
 	if err != nil {
+		if errors.Is(err, k8sclient.ErrInvalidProxyConfig) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 
@@ -251,15 +266,21 @@ func (s *PXCClusterService) UpdatePXCCluster(ctx context.Context, req *controlle
 }
 
 // DeletePXCCluster deletes PXC cluster.
+// NotFound is returned if cluster doesn't exist, including repeated deletion of the same cluster.
 func (s *PXCClusterService) DeletePXCCluster(ctx context.Context, req *controllerv1beta1.DeletePXCClusterRequest) (*controllerv1beta1.DeletePXCClusterResponse, error) {
-	client, err := k8sclient.New(ctx, req.KubeAuth.Kubeconfig)
+	client, err := k8sclient.New(ctx, req.KubeAuth.Kubeconfig, s.clientOpts...)
 	if err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 	defer client.Cleanup() //nolint:errcheck
 
-	err = client.DeletePXCCluster(ctx, req.Name)
+	err = client.DeletePXCCluster(ctx, req.Name, false)
 	if err != nil {
+		if errors.Is(err, k8sclient.ErrBackupInProgress) {
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		} else if errors.Is(err, k8sclient.ErrNotFound) {
+			return nil, status.Error(codes.NotFound, err.Error())
+		}
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 	return new(controllerv1beta1.DeletePXCClusterResponse), nil
@@ -267,7 +288,7 @@ func (s *PXCClusterService) DeletePXCCluster(ctx context.Context, req *controlle
 
 // RestartPXCCluster restarts PXC cluster.
 func (s *PXCClusterService) RestartPXCCluster(ctx context.Context, req *controllerv1beta1.RestartPXCClusterRequest) (*controllerv1beta1.RestartPXCClusterResponse, error) {
-	client, err := k8sclient.New(ctx, req.KubeAuth.Kubeconfig)
+	client, err := k8sclient.New(ctx, req.KubeAuth.Kubeconfig, s.clientOpts...)
 	if err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
 	}
@@ -282,7 +303,7 @@ func (s *PXCClusterService) RestartPXCCluster(ctx context.Context, req *controll
 
 // GetPXCClusterCredentials returns an PXC cluster connection credentials.
 func (s PXCClusterService) GetPXCClusterCredentials(ctx context.Context, req *controllerv1beta1.GetPXCClusterCredentialsRequest) (*controllerv1beta1.GetPXCClusterCredentialsResponse, error) {
-	client, err := k8sclient.New(ctx, req.KubeAuth.Kubeconfig)
+	client, err := k8sclient.New(ctx, req.KubeAuth.Kubeconfig, s.clientOpts...)
 	if err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
 	}
@@ -31,7 +31,27 @@ type NodeSummaryNode struct {
 // One gets this by requesting Kubernetes API endpoint:
 // /v1/nodes/<node-name>/proxy/stats/summary.
 type NodeSummary struct {
-	Node NodeSummaryNode `json:"node,omitempty"`
+	Node NodeSummaryNode   `json:"node,omitempty"`
+	Pods []PodStatsSummary `json:"pods,omitempty"`
+}
+
+// PodStatsSummary holds volume statistics of a pod inside Node's summary.
+type PodStatsSummary struct {
+	Volumes []VolumeStatsSummary `json:"volume,omitempty"`
+}
+
+// VolumeStatsSummary holds statistics of a pod's volume.
+type VolumeStatsSummary struct {
+	Name          string        `json:"name,omitempty"`
+	PVCRef        *PVCReference `json:"pvcRef,omitempty"`
+	UsedBytes     uint64        `json:"usedBytes,omitempty"`
+	CapacityBytes uint64        `json:"capacityBytes,omitempty"`
+}
+
+// PVCReference contains name and namespace of persistent volume claim the volume is backed by.
+type PVCReference struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
 }
 
 // NodeFileSystemSummary holds a summary of Node's filesystem.
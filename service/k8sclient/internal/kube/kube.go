@@ -23,6 +23,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"net/http"
 	"os"
 	"sort"
 	"strings"
@@ -36,7 +37,9 @@ import (
 	yaml3 "gopkg.in/yaml.v3"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
 	v1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -45,8 +48,11 @@ import (
 	yamlSerializer "k8s.io/apimachinery/pkg/runtime/serializer/yaml"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/duration"
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+	"k8s.io/apimachinery/pkg/util/wait"
 	yamlutil "k8s.io/apimachinery/pkg/util/yaml"
 	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/cli-runtime/pkg/resource"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/scheme"
@@ -58,7 +64,10 @@ import (
 	"k8s.io/client-go/tools/clientcmd"
 	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
 	"k8s.io/client-go/tools/reference"
+	watchtools "k8s.io/client-go/tools/watch"
+	"k8s.io/client-go/util/retry"
 
+	"github.com/percona-platform/dbaas-controller/service/k8sclient/internal/kube/pg"
 	"github.com/percona-platform/dbaas-controller/service/k8sclient/internal/kube/psmdb"
 	"github.com/percona-platform/dbaas-controller/service/k8sclient/internal/kube/pxc"
 )
@@ -71,10 +80,13 @@ const (
 	dbaasToolPath      = "/opt/dbaas-tools/bin"
 	PXCKind            = pxc.PXCKind
 	PSMDBKind          = psmdb.PSMDBKind
+	PGKind             = pg.PGKind
 	defaultChunkSize   = 500
 	configKind         = "Config"
 	apiVersion         = "v1"
 	defaultName        = "default"
+
+	deploymentPollInterval = 2 * time.Second
 )
 
 // Each level has 2 spaces for PrefixWriter
@@ -162,6 +174,7 @@ type Client struct {
 	clientset   *kubernetes.Clientset
 	pxcClient   *pxc.PerconaXtraDBClusterClient
 	psmdbClient *psmdb.PerconaServerMongoDBClient
+	pgClient    *pg.PerconaPGClusterClient
 	restConfig  *rest.Config
 	namespace   string
 }
@@ -195,13 +208,16 @@ func (g *configGetter) loadFromString() (*clientcmdapi.Config, error) {
 // kubernetes gives to pods. It's intended for clients that expect to be
 // running inside a pod running on kubernetes. It will return ErrNotInCluster
 // if called from a process not running in a kubernetes environment.
-func NewFromIncluster() (*Client, error) {
+func NewFromIncluster(opts ...Option) (*Client, error) {
 	config, err := rest.InClusterConfig()
 	if err != nil {
 		return nil, err
 	}
 	config.QPS = defaultQPSLimit
 	config.Burst = defaultBurstLimit
+	for _, opt := range opts {
+		opt(config)
+	}
 	clientset, err := kubernetes.NewForConfig(config)
 	if err != nil {
 		return nil, err
@@ -215,15 +231,84 @@ func NewFromIncluster() (*Client, error) {
 	return c, err
 }
 
+// Option configures REST config of the client.
+type Option func(*rest.Config)
+
+// WithImpersonation makes the client act as given user and groups,
+// so requests are attributed to them in Kubernetes audit logs.
+func WithImpersonation(user string, groups []string) Option {
+	return func(config *rest.Config) {
+		config.Impersonate = rest.ImpersonationConfig{UserName: user, Groups: groups}
+	}
+}
+
+// ConnectionPool configures idle connections kept by the client for reuse.
+// Zero fields keep client-go defaults: no limit of idle connections in total,
+// 25 idle connections per API server and 90 seconds idle timeout.
+//
+// API servers usually speak HTTP/2, then all requests are multiplexed over a single connection,
+// MaxIdleConns and MaxIdleConnsPerHost have no effect and IdleConnTimeout only defines
+// when that connection is closed after it becomes idle. Limits of idle connections matter
+// for HTTP/1.1 only, e.g. when HTTP/2 is disabled with DISABLE_HTTP2 environment variable
+// or not supported by a proxy in front of API server.
+type ConnectionPool struct {
+	MaxIdleConns        int
+	MaxIdleConnsPerHost int
+	IdleConnTimeout     time.Duration
+}
+
+// WithConnectionPool configures idle connections pool of the client.
+// Over HTTP/1.1 each request which doesn't find an idle connection opens a new one with TLS handshake,
+// so under concurrent load MaxIdleConnsPerHost should be close to the number of parallel requests.
+// Over HTTP/2 only IdleConnTimeout is used, see ConnectionPool.
+// Connections are pooled per TLS configuration and shared by all REST clients created from the config.
+func WithConnectionPool(pool ConnectionPool) Option {
+	return func(config *rest.Config) {
+		var mu sync.Mutex
+		transports := make(map[*http.Transport]*http.Transport)
+		config.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+			base, ok := rt.(*http.Transport)
+			if !ok {
+				return rt
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			// Base transport is cached by client-go and may be shared with other configs, so it's cloned once.
+			t, ok := transports[base]
+			if !ok {
+				t = base.Clone()
+				if pool.MaxIdleConns > 0 {
+					t.MaxIdleConns = pool.MaxIdleConns
+				}
+				if pool.MaxIdleConnsPerHost > 0 {
+					t.MaxIdleConnsPerHost = pool.MaxIdleConnsPerHost
+				}
+				if pool.IdleConnTimeout > 0 {
+					t.IdleConnTimeout = pool.IdleConnTimeout
+				}
+				// Cloned TLSNextProto hands HTTP/2 connections to the pool of the base transport
+				// which reads settings from the base, so HTTP/2 is configured for the clone itself.
+				t.TLSNextProto = nil
+				t = utilnet.SetTransportDefaults(t)
+				transports[base] = t
+			}
+			return t
+		})
+	}
+}
+
 // NewFromKubeConfigString creates a new client for the given config string.
 // It's intended for clients that expect to be running outside of a cluster
-func NewFromKubeConfigString(kubeconfig string) (*Client, error) {
+func NewFromKubeConfigString(kubeconfig string, opts ...Option) (*Client, error) {
 	config, err := clientcmd.BuildConfigFromKubeconfigGetter("", NewConfigGetter(kubeconfig).loadFromString)
 	if err != nil {
 		return nil, err
 	}
 	config.QPS = defaultQPSLimit
 	config.Burst = defaultBurstLimit
+	for _, opt := range opts {
+		opt(config)
+	}
 	clientset, err := kubernetes.NewForConfig(config)
 	if err != nil {
 		return nil, err
@@ -258,8 +343,13 @@ func (c *Client) initOperatorClients() error {
 	if err != nil {
 		return err
 	}
+	pgClient, err := pg.NewForConfig(c.restConfig)
+	if err != nil {
+		return err
+	}
 	c.pxcClient = pxcClient
 	c.psmdbClient = psmdbClient
+	c.pgClient = pgClient
 	_, err = c.GetServerVersion(context.Background())
 	return err
 }
@@ -308,9 +398,16 @@ func (c *Client) Delete(ctx context.Context, obj runtime.Object) error {
 
 // Apply applies object against the k8s cluster
 func (c *Client) Apply(ctx context.Context, obj runtime.Object) error {
+	_, err := c.apply(ctx, obj, false)
+	return err
+}
+
+// apply applies object against the k8s cluster and returns true if object didn't exist before.
+// If dryRun is true, object is validated by the server but not persisted.
+func (c *Client) apply(ctx context.Context, obj runtime.Object, dryRun bool) (bool, error) {
 	groupResources, err := restmapper.GetAPIGroupResources(c.clientset.Discovery())
 	if err != nil {
-		return err
+		return false, err
 	}
 	mapper := restmapper.NewDiscoveryRESTMapper(groupResources)
 
@@ -318,53 +415,383 @@ func (c *Client) Apply(ctx context.Context, obj runtime.Object) error {
 	gk := schema.GroupKind{Group: gvk.Group, Kind: gvk.Kind}
 	mapping, err := mapper.RESTMapping(gk, gvk.Version)
 	if err != nil {
-		return err
+		return false, err
 	}
 	namespace, name, err := c.retrieveMetaFromObject(obj)
 	if err != nil {
-		return err
+		return false, err
 	}
 	cli, err := c.resourceClient(mapping.GroupVersionKind.GroupVersion())
 	if err != nil {
-		return err
+		return false, err
 	}
-	helper := resource.NewHelper(cli, mapping)
-	err = applyObject(helper, namespace, name, obj)
-	return err
+	helper := resource.NewHelper(cli, mapping).DryRun(dryRun)
+	return applyObject(helper, namespace, name, obj)
+}
+
+// FileErrorMode defines how ApplyFile and DeleteFile handle an error of a single manifest object.
+type FileErrorMode int
+
+const (
+	// StopOnError stops at the first failed object and returns its error as is.
+	StopOnError FileErrorMode = iota
+	// ContinueOnError processes all objects and returns FileError listing failed ones.
+	ContinueOnError
+	// RollbackOnError stops at the first failed object and deletes objects created so far in reverse order.
+	// Objects which existed before are left updated. DeleteFile handles it as StopOnError.
+	RollbackOnError
+)
+
+// ObjectError is an error of applying or deleting a single manifest object.
+type ObjectError struct {
+	Kind string
+	Name string
+	Err  error
+}
+
+// Error implements error interface.
+func (e *ObjectError) Error() string {
+	return fmt.Sprintf("%s %q: %s", e.Kind, e.Name, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *ObjectError) Unwrap() error {
+	return e.Err
+}
+
+func newObjectError(obj runtime.Object, err error) *ObjectError {
+	name, _ := meta.NewAccessor().Name(obj)
+	return &ObjectError{Kind: obj.GetObjectKind().GroupVersionKind().Kind, Name: name, Err: err}
+}
+
+// FileError lists manifest objects which failed to be applied, deleted or rolled back.
+type FileError struct {
+	Errors []*ObjectError
+}
+
+// Error implements error interface.
+func (e *FileError) Error() string {
+	msgs := make([]string, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		msgs = append(msgs, err.Error())
+	}
+	return fmt.Sprintf("%d object(s) failed: %s", len(e.Errors), strings.Join(msgs, "; "))
+}
+
+// isRetriableError returns true for errors caused by API server load or concurrent updates.
+func isRetriableError(err error) bool {
+	return apierrors.IsConflict(err) || apierrors.IsServerTimeout(err) || apierrors.IsTimeout(err) ||
+		apierrors.IsTooManyRequests(err) || apierrors.IsServiceUnavailable(err)
 }
 
 // DeleteFile accepts manifest file contents parses into []runtime.Object
-// and deletes them from the cluster
-func (c *Client) DeleteFile(ctx context.Context, fileBytes []byte) error {
+// and deletes them from the cluster. Errors of single objects are handled according to mode.
+func (c *Client) DeleteFile(ctx context.Context, fileBytes []byte, mode FileErrorMode) error {
 	objs, err := c.getObjects(fileBytes)
 	if err != nil {
 		return err
 	}
-	for i := range objs {
-		err := c.Delete(ctx, objs[i])
-		if err != nil {
+	// Deleted objects can't be restored.
+	if mode == RollbackOnError {
+		mode = StopOnError
+	}
+	return processObjects(objs, mode, func(obj runtime.Object) (bool, error) {
+		return false, retry.OnError(retry.DefaultBackoff, isRetriableError, func() error {
+			return c.Delete(ctx, obj)
+		})
+	}, nil)
+}
+
+// ApplyFile accepts manifest file contents, parses into []runtime.Object
+// and applies them against the cluster. Errors of single objects are handled according to mode.
+func (c *Client) ApplyFile(ctx context.Context, fileBytes []byte, mode FileErrorMode) error {
+	objs, err := c.getObjects(fileBytes)
+	if err != nil {
+		return err
+	}
+	return processObjects(objs, mode, func(obj runtime.Object) (bool, error) {
+		var created bool
+		err := retry.OnError(retry.DefaultBackoff, isRetriableError, func() error {
+			var err error
+			created, err = c.apply(ctx, obj, false)
 			return err
+		})
+		return created, err
+	}, func(obj runtime.Object) error {
+		return c.Delete(ctx, obj)
+	})
+}
+
+// ApplyFileDryRun accepts manifest file contents, parses into []runtime.Object
+// and applies them using server-side dry run without changing the cluster.
+// FileError listing all invalid objects is returned.
+func (c *Client) ApplyFileDryRun(ctx context.Context, fileBytes []byte) error {
+	objs, err := c.getObjects(fileBytes)
+	if err != nil {
+		return err
+	}
+	return processObjects(objs, ContinueOnError, func(obj runtime.Object) (bool, error) {
+		return c.apply(ctx, obj, true)
+	}, nil)
+}
+
+// processObjects calls process for each object in order handling errors according to mode.
+// process returns true if object was created, such objects are passed to rollback in reverse order.
+func processObjects(
+	objs []runtime.Object,
+	mode FileErrorMode,
+	process func(runtime.Object) (bool, error),
+	rollback func(runtime.Object) error,
+) error {
+	var created []runtime.Object
+	var failed []*ObjectError
+	for _, obj := range objs {
+		ok, err := process(obj)
+		if err == nil {
+			if ok {
+				created = append(created, obj)
+			}
+			continue
 		}
+
+		switch mode {
+		case ContinueOnError:
+			failed = append(failed, newObjectError(obj, err))
+			continue
+		case RollbackOnError:
+			failed = append(failed, newObjectError(obj, err))
+			for i := len(created) - 1; i >= 0; i-- {
+				if err := rollback(created[i]); err != nil {
+					failed = append(failed, newObjectError(created[i], errors.Wrap(err, "rollback failed")))
+				}
+			}
+			return &FileError{Errors: failed}
+		default:
+			return err
+		}
+	}
+	if len(failed) != 0 {
+		return &FileError{Errors: failed}
 	}
 	return nil
 }
 
-// ApplyFile accepts manifest file contents, parses into []runtime.Object
-// and applies them against the cluster
-func (c *Client) ApplyFile(ctx context.Context, fileBytes []byte) error {
+// GetDeploymentNames returns names of deployments defined in given manifests.
+func (c *Client) GetDeploymentNames(fileBytes []byte) ([]string, error) {
+	return c.getObjectNames(fileBytes, "Deployment")
+}
+
+// GetCRDNames returns names of custom resource definitions defined in given manifests.
+func (c *Client) GetCRDNames(fileBytes []byte) ([]string, error) {
+	return c.getObjectNames(fileBytes, "CustomResourceDefinition")
+}
+
+func (c *Client) getObjectNames(fileBytes []byte, kind string) ([]string, error) {
+	objs, err := c.getObjects(fileBytes)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, obj := range objs {
+		u, ok := obj.(*unstructured.Unstructured)
+		if !ok || u.GetKind() != kind {
+			continue
+		}
+		names = append(names, u.GetName())
+	}
+	return names, nil
+}
+
+// WaitForDeploymentAvailable waits until deployment has Available condition set to true
+// or context is done.
+func (c *Client) WaitForDeploymentAvailable(ctx context.Context, name string) error {
+	return wait.PollImmediateUntilWithContext(ctx, deploymentPollInterval, func(ctx context.Context) (bool, error) {
+		deployment, err := c.GetDeployment(ctx, name)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				return false, nil
+			}
+			return false, err
+		}
+		for _, cond := range deployment.Status.Conditions {
+			if cond.Type == appsv1.DeploymentAvailable && cond.Status == corev1.ConditionTrue {
+				return true, nil
+			}
+		}
+		return false, nil
+	})
+}
+
+// WaitForCondition waits until all objects of manifest file have the condition set to true
+// or context is done. Condition is a condition type optionally followed by "=" and expected status,
+// e.g. "Established" or "Ready=False".
+func (c *Client) WaitForCondition(ctx context.Context, condition string, fileBytes []byte) error {
 	objs, err := c.getObjects(fileBytes)
 	if err != nil {
 		return err
 	}
-	for i := range objs {
-		err := c.Apply(ctx, objs[i])
+
+	groupResources, err := restmapper.GetAPIGroupResources(c.clientset.Discovery())
+	if err != nil {
+		return err
+	}
+	mapper := restmapper.NewDiscoveryRESTMapper(groupResources)
+
+	for _, obj := range objs {
+		gvk := obj.GetObjectKind().GroupVersionKind()
+		mapping, err := mapper.RESTMapping(schema.GroupKind{Group: gvk.Group, Kind: gvk.Kind}, gvk.Version)
+		if err != nil {
+			return err
+		}
+		namespace, name, err := c.retrieveMetaFromObject(obj)
 		if err != nil {
 			return err
 		}
+		cli, err := c.resourceClient(mapping.GroupVersionKind.GroupVersion())
+		if err != nil {
+			return err
+		}
+		helper := resource.NewHelper(cli, mapping)
+		if err = waitForObjectCondition(ctx, helper, namespace, name, condition); err != nil {
+			return newObjectError(obj, err)
+		}
 	}
 	return nil
 }
 
+// waitForObjectCondition gets the object and, if the condition isn't met yet,
+// watches it starting from the returned resource version. Closed watches are restarted.
+func waitForObjectCondition(ctx context.Context, helper *resource.Helper, namespace, name, condition string) error {
+	for {
+		obj, err := helper.Get(namespace, name)
+		if err != nil {
+			return err
+		}
+		met, err := hasCondition(obj, condition)
+		if err != nil || met {
+			return err
+		}
+		resourceVersion, err := meta.NewAccessor().ResourceVersion(obj)
+		if err != nil {
+			return err
+		}
+		w, err := helper.WatchSingle(namespace, name, resourceVersion)
+		if err != nil {
+			return err
+		}
+		err = untilCondition(ctx, w, helper.Resource, name, condition)
+		if !errors.Is(err, watchtools.ErrWatchClosed) {
+			return err
+		}
+	}
+}
+
+// untilCondition consumes watch events of the object until it has the condition.
+// It returns watchtools.ErrWatchClosed if watch is closed before that.
+func untilCondition(ctx context.Context, w watch.Interface, resource, name, condition string) error {
+	_, err := watchtools.UntilWithoutRetry(ctx, w, func(event watch.Event) (bool, error) {
+		switch event.Type {
+		case watch.Deleted:
+			return false, errors.Errorf("%s %q was deleted", resource, name)
+		case watch.Error:
+			return false, apierrors.FromObject(event.Object)
+		}
+		return hasCondition(event.Object, condition)
+	})
+	return err
+}
+
+// hasCondition returns true if object's status has the condition with expected status, "True" by default.
+func hasCondition(obj runtime.Object, condition string) (bool, error) {
+	condType, condStatus, found := strings.Cut(condition, "=")
+	if !found {
+		condStatus = string(metav1.ConditionTrue)
+	}
+	u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return false, err
+	}
+	conditions, _, err := unstructured.NestedSlice(u, "status", "conditions")
+	if err != nil {
+		return false, err
+	}
+	for _, c := range conditions {
+		cond, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		t, _ := cond["type"].(string)
+		s, _ := cond["status"].(string)
+		if strings.EqualFold(t, condType) && strings.EqualFold(s, condStatus) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// CountCustomResources returns number of custom resources in all namespaces
+// for each custom resource definition in the manifest, keyed by CRD name.
+// Resources of CRDs which aren't installed are not counted.
+func (c *Client) CountCustomResources(ctx context.Context, crdManifest []byte) (map[string]int, error) {
+	objs, err := c.getObjects(crdManifest)
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int)
+	for _, obj := range objs {
+		u, ok := obj.(*unstructured.Unstructured)
+		if !ok || u.GetKind() != "CustomResourceDefinition" {
+			continue
+		}
+		gvr, err := crdResource(u)
+		if err != nil {
+			return nil, err
+		}
+		cli, err := c.resourceClient(gvr.GroupVersion())
+		if err != nil {
+			return nil, err
+		}
+		raw, err := cli.Get().Resource(gvr.Resource).Do(ctx).Raw()
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return nil, errors.Wrapf(err, "failed to list %s", gvr.Resource)
+		}
+		var list struct {
+			Items []json.RawMessage `json:"items"`
+		}
+		if err = json.Unmarshal(raw, &list); err != nil {
+			return nil, errors.Wrapf(err, "failed to parse %s", gvr.Resource)
+		}
+		counts[u.GetName()] = len(list.Items)
+	}
+	return counts, nil
+}
+
+// crdResource returns resource defined by custom resource definition in its storage version.
+// Both apiextensions.k8s.io/v1 and v1beta1 definitions are supported.
+func crdResource(crd *unstructured.Unstructured) (schema.GroupVersionResource, error) {
+	group, _, _ := unstructured.NestedString(crd.Object, "spec", "group")
+	plural, _, _ := unstructured.NestedString(crd.Object, "spec", "names", "plural")
+	version, _, _ := unstructured.NestedString(crd.Object, "spec", "version")
+	versions, _, _ := unstructured.NestedSlice(crd.Object, "spec", "versions")
+	for _, v := range versions {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := m["name"].(string)
+		if storage, _ := m["storage"].(bool); storage || version == "" {
+			version = name
+		}
+	}
+	if group == "" || plural == "" || version == "" {
+		return schema.GroupVersionResource{}, errors.Errorf("custom resource definition %q has no group, plural name or version", crd.GetName())
+	}
+	return schema.GroupVersionResource{Group: group, Version: version, Resource: plural}, nil
+}
+
 func (c *Client) getObjects(f []byte) ([]runtime.Object, error) {
 	objs := []runtime.Object{}
 	decoder := yamlutil.NewYAMLOrJSONDecoder(bytes.NewReader(f), 100)
@@ -400,8 +827,33 @@ func (c *Client) GetSecret(ctx context.Context, name string) (*corev1.Secret, er
 	return c.clientset.CoreV1().Secrets(c.namespace).Get(ctx, name, metav1.GetOptions{})
 }
 
+// GetConfigMap returns config map by provided name.
+func (c *Client) GetConfigMap(ctx context.Context, name string) (*corev1.ConfigMap, error) {
+	return c.clientset.CoreV1().ConfigMaps(c.namespace).Get(ctx, name, metav1.GetOptions{})
+}
+
+// GetServiceAccount returns service account by provided name.
+func (c *Client) GetServiceAccount(ctx context.Context, name string) (*corev1.ServiceAccount, error) {
+	return c.clientset.CoreV1().ServiceAccounts(c.namespace).Get(ctx, name, metav1.GetOptions{})
+}
+
+// GetPriorityClass returns priority class by provided name.
+func (c *Client) GetPriorityClass(ctx context.Context, name string) (*schedulingv1.PriorityClass, error) {
+	return c.clientset.SchedulingV1().PriorityClasses().Get(ctx, name, metav1.GetOptions{})
+}
+
+// GetServerVersion returns version of Kubernetes API server.
+// Unlike discovery client's ServerVersion, the request is canceled with ctx.
 func (c *Client) GetServerVersion(ctx context.Context) (*version.Info, error) {
-	return c.clientset.Discovery().ServerVersion()
+	body, err := c.clientset.Discovery().RESTClient().Get().AbsPath("/version").Do(ctx).Raw()
+	if err != nil {
+		return nil, err
+	}
+	var info version.Info
+	if err = json.Unmarshal(body, &info); err != nil {
+		return nil, errors.Wrap(err, "cannot parse server version")
+	}
+	return &info, nil
 }
 
 // GetAPIVersions returns apiversions
@@ -420,6 +872,11 @@ func (c *Client) GetPersistentVolumes(ctx context.Context) (*corev1.PersistentVo
 	return c.clientset.CoreV1().PersistentVolumes().List(ctx, metav1.ListOptions{})
 }
 
+// GetPersistentVolumeClaims returns Persistent Volume Claims matching label selector.
+func (c *Client) GetPersistentVolumeClaims(ctx context.Context, labelSelector string) (*corev1.PersistentVolumeClaimList, error) {
+	return c.clientset.CoreV1().PersistentVolumeClaims(c.namespace).List(ctx, metav1.ListOptions{LabelSelector: labelSelector})
+}
+
 // GetPods returns list of pods
 func (c *Client) GetPods(ctx context.Context, namespace, labelSelector string) (*corev1.PodList, error) {
 	options := metav1.ListOptions{}
@@ -443,14 +900,42 @@ func (c *Client) GetNodes(ctx context.Context) (*corev1.NodeList, error) {
 	return c.clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
 }
 
-// GetLogs returns logs for pod
-func (c *Client) GetLogs(ctx context.Context, pod, container string) (string, error) {
-	defaultLogLines := int64(3000)
-	options := new(corev1.PodLogOptions)
+// GetNodeStatsSummary returns raw JSON stats summary of the node read through API server proxy.
+func (c *Client) GetNodeStatsSummary(ctx context.Context, node string) ([]byte, error) {
+	return c.clientset.CoreV1().RESTClient().Get().Resource("nodes").Name(node).SubResource("proxy").Suffix("stats/summary").DoRaw(ctx)
+}
+
+// GetPod returns pod by provided name.
+func (c *Client) GetPod(ctx context.Context, name string) (*corev1.Pod, error) {
+	return c.clientset.CoreV1().Pods(c.namespace).Get(ctx, name, metav1.GetOptions{})
+}
+
+// GetLogs returns last tailLines lines of logs for pod. All logs are returned if tailLines is 0.
+// If previous is true, logs of the previous instance of terminated container are returned.
+func (c *Client) GetLogs(ctx context.Context, pod, container string, tailLines int64, previous bool) (string, error) {
+	if tailLines < 0 {
+		return "", errors.Errorf("tail lines must not be negative, got %d", tailLines)
+	}
+	options := &corev1.PodLogOptions{Previous: previous}
 	if container != "" {
 		options.Container = container
 	}
-	options.TailLines = &defaultLogLines
+	if tailLines > 0 {
+		options.TailLines = &tailLines
+	}
+	return c.streamLogs(ctx, pod, options)
+}
+
+// GetLogsSince returns logs for pod written after given time.
+func (c *Client) GetLogsSince(ctx context.Context, pod, container string, since time.Time) (string, error) {
+	options := &corev1.PodLogOptions{
+		Container: container,
+		SinceTime: &metav1.Time{Time: since},
+	}
+	return c.streamLogs(ctx, pod, options)
+}
+
+func (c *Client) streamLogs(ctx context.Context, pod string, options *corev1.PodLogOptions) (string, error) {
 	buf := new(bytes.Buffer)
 
 	req := c.clientset.CoreV1().Pods(c.namespace).GetLogs(pod, options)
@@ -470,6 +955,21 @@ func (c *Client) GetStatefulSet(ctx context.Context, name string) (*appsv1.State
 	return c.clientset.AppsV1().StatefulSets(c.namespace).Get(ctx, name, metav1.GetOptions{})
 }
 
+// GetService returns service by name.
+func (c *Client) GetService(ctx context.Context, name string) (*corev1.Service, error) {
+	return c.clientset.CoreV1().Services(c.namespace).Get(ctx, name, metav1.GetOptions{})
+}
+
+// ListServices returns services matching label selector.
+func (c *Client) ListServices(ctx context.Context, labelSelector string) (*corev1.ServiceList, error) {
+	return c.clientset.CoreV1().Services(c.namespace).List(ctx, metav1.ListOptions{LabelSelector: labelSelector})
+}
+
+// ListStatefulSets returns statefulsets matching label selector.
+func (c *Client) ListStatefulSets(ctx context.Context, labelSelector string) (*appsv1.StatefulSetList, error) {
+	return c.clientset.AppsV1().StatefulSets(c.namespace).List(ctx, metav1.ListOptions{LabelSelector: labelSelector})
+}
+
 // RestartStatefulSet finds statefulset by name and restarts it.
 func (c *Client) RestartStatefulSet(ctx context.Context, name string) (*appsv1.StatefulSet, error) {
 	patchData := fmt.Sprintf(restartTemplate, time.Now().UTC().Format(time.RFC3339))
@@ -495,6 +995,16 @@ func (c *Client) PatchPXCCluster(ctx context.Context, name string, pt types.Patc
 	return c.pxcClient.PXCClusters(c.namespace).Patch(ctx, name, pt, data, opts)
 }
 
+// WatchPXCClusters watches PXC clusters starting from given resource version.
+func (c *Client) WatchPXCClusters(ctx context.Context, resourceVersion string) (watch.Interface, error) {
+	return c.pxcClient.PXCClusters(c.namespace).Watch(ctx, metav1.ListOptions{ResourceVersion: resourceVersion})
+}
+
+// ListPXCClusterBackups returns list of PXC cluster backups.
+func (c *Client) ListPXCClusterBackups(ctx context.Context) (*pxcv1.PerconaXtraDBClusterBackupList, error) {
+	return c.pxcClient.PXCBackups(c.namespace).List(ctx, metav1.ListOptions{})
+}
+
 // ListPSMDBClusters returns list of managed PSMDB clusters.
 func (c *Client) ListPSMDBClusters(ctx context.Context) (*psmdbv1.PerconaServerMongoDBList, error) {
 	return c.psmdbClient.PSMDBClusters(c.namespace).List(ctx, metav1.ListOptions{})
@@ -505,11 +1015,31 @@ func (c *Client) GetPSMDBCluster(ctx context.Context, name string) (*psmdbv1.Per
 	return c.psmdbClient.PSMDBClusters(c.namespace).Get(ctx, name, metav1.GetOptions{})
 }
 
+// WatchPSMDBClusters watches PSMDB clusters starting from given resource version.
+func (c *Client) WatchPSMDBClusters(ctx context.Context, resourceVersion string) (watch.Interface, error) {
+	return c.psmdbClient.PSMDBClusters(c.namespace).Watch(ctx, metav1.ListOptions{ResourceVersion: resourceVersion})
+}
+
 // PatchPSMDBCluster patches CR of managed PSMDB cluster.
 func (c *Client) PatchPSMDBCluster(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions) (*psmdbv1.PerconaServerMongoDB, error) {
 	return c.psmdbClient.PSMDBClusters(c.namespace).Patch(ctx, name, pt, data, opts)
 }
 
+// ListPGClusters returns list of managed PostgreSQL clusters.
+func (c *Client) ListPGClusters(ctx context.Context) (*pg.PerconaPGClusterList, error) {
+	return c.pgClient.PGClusters(c.namespace).List(ctx, metav1.ListOptions{})
+}
+
+// GetPGCluster returns PostgreSQL cluster by provided name.
+func (c *Client) GetPGCluster(ctx context.Context, name string) (*pg.PerconaPGCluster, error) {
+	return c.pgClient.PGClusters(c.namespace).Get(ctx, name, metav1.GetOptions{})
+}
+
+// PatchPGCluster patches CR of managed PostgreSQL cluster.
+func (c *Client) PatchPGCluster(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions) (*pg.PerconaPGCluster, error) {
+	return c.pgClient.PGClusters(c.namespace).Patch(ctx, name, pt, data, opts)
+}
+
 // GetDeployment finds deployment.
 func (c *Client) GetDeployment(ctx context.Context, name string) (*appsv1.Deployment, error) {
 	return c.clientset.AppsV1().Deployments(c.namespace).Get(ctx, name, metav1.GetOptions{})
@@ -744,19 +1274,16 @@ func (c *Client) retrieveMetaFromObject(obj runtime.Object) (namespace, name str
 	return
 }
 
-func applyObject(helper *resource.Helper, namespace, name string, obj runtime.Object) error {
+func applyObject(helper *resource.Helper, namespace, name string, obj runtime.Object) (bool, error) {
 	if _, err := helper.Get(namespace, name); err != nil {
 		_, err = helper.Create(namespace, false, obj)
 		if err != nil {
-			return err
-		}
-	} else {
-		_, err = helper.Replace(namespace, name, true, obj)
-		if err != nil {
-			return err
+			return false, err
 		}
+		return true, nil
 	}
-	return nil
+	_, err := helper.Replace(namespace, name, true, obj)
+	return false, err
 }
 
 func deleteObject(helper *resource.Helper, namespace, name string) error {
@@ -19,17 +19,36 @@ package kube
 
 import (
 	"context"
+	"encoding/json"
 	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	watchtools "k8s.io/client-go/tools/watch"
 )
 
 func TestKubeClient(t *testing.T) {
@@ -69,7 +88,7 @@ spec:
 	assert.NoError(t, err)
 	assert.Equal(t, 0, len(podList.Items))
 
-	err = k.ApplyFile(context.Background(), []byte(deployment))
+	err = k.ApplyFile(context.Background(), []byte(deployment), StopOnError)
 
 	assert.NoError(t, err)
 
@@ -92,14 +111,43 @@ spec:
 	assert.NoError(t, err)
 	assert.NotEqual(t, 0, len(nodes.Items))
 
-	logs, err := k.GetLogs(context.Background(), pods.Items[0].Name, pods.Items[0].Spec.Containers[0].Name)
+	logs, err := k.GetLogs(context.Background(), pods.Items[0].Name, pods.Items[0].Spec.Containers[0].Name, 3000, false)
 	assert.NoError(t, err)
 	assert.NotEqual(t, 0, len(logs))
 
-	assert.NoError(t, k.DeleteFile(context.Background(), []byte(deployment)))
+	assert.NoError(t, k.DeleteFile(context.Background(), []byte(deployment), StopOnError))
 	time.Sleep(time.Second)
 }
 
+func TestGetDeploymentNames(t *testing.T) {
+	t.Parallel()
+	bundle := `
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: perconaxtradbclusters.pxc.percona.com
+---
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: percona-xtradb-cluster-operator
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: percona-xtradb-cluster-operator
+spec:
+  replicas: 1
+`
+	names, err := new(Client).GetDeploymentNames([]byte(bundle))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"percona-xtradb-cluster-operator"}, names)
+
+	crds, err := new(Client).GetCRDNames([]byte(bundle))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"perconaxtradbclusters.pxc.percona.com"}, crds)
+}
+
 func TestInCluster(t *testing.T) {
 	t.Parallel()
 	_, err := NewFromIncluster()
@@ -162,3 +210,429 @@ func TestGetSecret(t *testing.T) {
 	err = k.Delete(context.Background(), secret)
 	assert.NoError(t, err)
 }
+
+func TestProcessObjects(t *testing.T) {
+	t.Parallel()
+
+	obj := func(kind, name string) runtime.Object {
+		u := new(unstructured.Unstructured)
+		u.SetKind(kind)
+		u.SetName(name)
+		return u
+	}
+	objs := []runtime.Object{obj("ServiceAccount", "a"), obj("Role", "b"), obj("RoleBinding", "c"), obj("Deployment", "d")}
+	failErr := errors.New("admission webhook denied")
+	process := func(o runtime.Object) (bool, error) {
+		name, _ := meta.NewAccessor().Name(o)
+		switch name {
+		case "b":
+			return false, nil // existed before
+		case "c":
+			return false, failErr
+		default:
+			return true, nil
+		}
+	}
+
+	t.Run("StopOnError", func(t *testing.T) {
+		t.Parallel()
+		var processed int
+		err := processObjects(objs, StopOnError, func(o runtime.Object) (bool, error) {
+			processed++
+			return process(o)
+		}, nil)
+		assert.Equal(t, failErr, err)
+		assert.Equal(t, 3, processed)
+	})
+
+	t.Run("ContinueOnError", func(t *testing.T) {
+		t.Parallel()
+		var processed int
+		err := processObjects(objs, ContinueOnError, func(o runtime.Object) (bool, error) {
+			processed++
+			return process(o)
+		}, nil)
+		var fileErr *FileError
+		require.ErrorAs(t, err, &fileErr)
+		require.Len(t, fileErr.Errors, 1)
+		assert.Equal(t, "RoleBinding", fileErr.Errors[0].Kind)
+		assert.ErrorIs(t, fileErr.Errors[0], failErr)
+		assert.Equal(t, `1 object(s) failed: RoleBinding "c": admission webhook denied`, err.Error())
+		assert.Equal(t, 4, processed)
+	})
+
+	t.Run("RollbackOnError", func(t *testing.T) {
+		t.Parallel()
+		var rolledBack []string
+		err := processObjects(objs, RollbackOnError, process, func(o runtime.Object) error {
+			name, _ := meta.NewAccessor().Name(o)
+			rolledBack = append(rolledBack, name)
+			return nil
+		})
+		var fileErr *FileError
+		require.ErrorAs(t, err, &fileErr)
+		assert.Len(t, fileErr.Errors, 1)
+		assert.Equal(t, []string{"a"}, rolledBack)
+	})
+}
+
+func TestWithImpersonation(t *testing.T) {
+	t.Parallel()
+
+	config := new(rest.Config)
+	WithImpersonation("jane", []string{"dev"})(config)
+	assert.Equal(t, rest.ImpersonationConfig{UserName: "jane", Groups: []string{"dev"}}, config.Impersonate)
+}
+
+func TestHasCondition(t *testing.T) {
+	t.Parallel()
+
+	crd := &unstructured.Unstructured{Object: map[string]interface{}{
+		"status": map[string]interface{}{
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "NamesAccepted", "status": "True"},
+				map[string]interface{}{"type": "Established", "status": "False"},
+			},
+		},
+	}}
+	for condition, expected := range map[string]bool{
+		"NamesAccepted":     true,
+		"namesaccepted":     true,
+		"Established":       false,
+		"Established=False": true,
+		"Terminating":       false,
+	} {
+		met, err := hasCondition(crd, condition)
+		require.NoError(t, err)
+		assert.Equal(t, expected, met, condition)
+	}
+
+	met, err := hasCondition(new(unstructured.Unstructured), "Established")
+	require.NoError(t, err)
+	assert.False(t, met)
+}
+
+func TestUntilCondition(t *testing.T) {
+	t.Parallel()
+
+	withCondition := func(condType, status string) runtime.Object {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"status": map[string]interface{}{
+				"conditions": []interface{}{map[string]interface{}{"type": condType, "status": status}},
+			},
+		}}
+	}
+
+	t.Run("WaitsForRequestedCondition", func(t *testing.T) {
+		t.Parallel()
+
+		w := watch.NewFakeWithChanSize(3, false)
+		w.Modify(withCondition("NamesAccepted", "True"))
+		w.Modify(withCondition("Established", "False"))
+		w.Modify(withCondition("Established", "True"))
+		err := untilCondition(context.Background(), w, "customresourcedefinitions", "test", "Established")
+		require.NoError(t, err)
+		assert.Empty(t, w.ResultChan(), "all events should be consumed")
+	})
+
+	t.Run("Deleted", func(t *testing.T) {
+		t.Parallel()
+
+		w := watch.NewFakeWithChanSize(1, false)
+		w.Delete(withCondition("Established", "False"))
+		err := untilCondition(context.Background(), w, "customresourcedefinitions", "test", "Established")
+		assert.EqualError(t, err, `customresourcedefinitions "test" was deleted`)
+	})
+
+	t.Run("Closed", func(t *testing.T) {
+		t.Parallel()
+
+		w := watch.NewFakeWithChanSize(1, false)
+		w.Modify(withCondition("NamesAccepted", "True"))
+		w.Stop()
+		err := untilCondition(context.Background(), w, "customresourcedefinitions", "test", "Established")
+		assert.ErrorIs(t, err, watchtools.ErrWatchClosed)
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		err := untilCondition(ctx, watch.NewFake(), "customresourcedefinitions", "test", "Established")
+		assert.Equal(t, wait.ErrWaitTimeout, err)
+	})
+}
+
+func TestWithConnectionPool(t *testing.T) {
+	t.Parallel()
+
+	config := new(rest.Config)
+	WithConnectionPool(ConnectionPool{MaxIdleConnsPerHost: 50, IdleConnTimeout: time.Minute})(config)
+	base := &http.Transport{MaxIdleConns: 10, MaxIdleConnsPerHost: 25, IdleConnTimeout: 90 * time.Second}
+
+	rt := config.WrapTransport(base)
+	transport, ok := rt.(*http.Transport)
+	require.True(t, ok)
+	assert.NotSame(t, base, transport, "shared base transport shouldn't be modified")
+	assert.Equal(t, 10, transport.MaxIdleConns)
+	assert.Equal(t, 50, transport.MaxIdleConnsPerHost)
+	assert.Equal(t, time.Minute, transport.IdleConnTimeout)
+	assert.Equal(t, 25, base.MaxIdleConnsPerHost)
+
+	assert.Same(t, transport, config.WrapTransport(base), "pool should be reused by clients of the same config")
+}
+
+func TestWithConnectionPoolHTTP2(t *testing.T) {
+	t.Parallel()
+
+	var closed int32
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	srv.EnableHTTP2 = true
+	srv.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateClosed {
+			atomic.AddInt32(&closed, 1)
+		}
+	}
+	srv.StartTLS()
+	defer srv.Close()
+
+	// Base transport is configured for HTTP/2 the same way client-go does it.
+	base := utilnet.SetTransportDefaults(&http.Transport{
+		TLSClientConfig: srv.Client().Transport.(*http.Transport).TLSClientConfig.Clone(), //nolint:forcetypeassert
+		IdleConnTimeout: time.Hour,
+	})
+	config := new(rest.Config)
+	WithConnectionPool(ConnectionPool{IdleConnTimeout: 50 * time.Millisecond})(config)
+	client := &http.Client{Transport: config.WrapTransport(base)}
+
+	resp, err := client.Get(srv.URL)
+	require.NoError(t, err)
+	resp.Body.Close() //nolint:errcheck
+	assert.Equal(t, 2, resp.ProtoMajor)
+
+	// Idle HTTP/2 connection is closed after timeout of the pool, not of the base transport.
+	assert.Eventually(t, func() bool { return atomic.LoadInt32(&closed) == 1 }, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestCRDResource(t *testing.T) {
+	t.Parallel()
+
+	crd := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"group": "pxc.percona.com",
+			"names": map[string]interface{}{"plural": "perconaxtradbclusterbackups"},
+			"versions": []interface{}{
+				map[string]interface{}{"name": "v1alpha1", "storage": false},
+				map[string]interface{}{"name": "v1", "storage": true},
+				map[string]interface{}{"name": "v2", "storage": false},
+			},
+		},
+	}}
+	gvr, err := crdResource(crd)
+	require.NoError(t, err)
+	assert.Equal(t, schema.GroupVersionResource{Group: "pxc.percona.com", Version: "v1", Resource: "perconaxtradbclusterbackups"}, gvr)
+
+	v1beta1 := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"group":   "pg.percona.com",
+			"version": "v1",
+			"names":   map[string]interface{}{"plural": "perconapgclusters"},
+		},
+	}}
+	gvr, err = crdResource(v1beta1)
+	require.NoError(t, err)
+	assert.Equal(t, schema.GroupVersionResource{Group: "pg.percona.com", Version: "v1", Resource: "perconapgclusters"}, gvr)
+
+	_, err = crdResource(new(unstructured.Unstructured))
+	assert.Error(t, err)
+}
+
+func TestGetLogsNegativeTailLines(t *testing.T) {
+	t.Parallel()
+
+	_, err := new(Client).GetLogs(context.Background(), "pod-0", "mongod", -1, false)
+	assert.EqualError(t, err, "tail lines must not be negative, got -1")
+}
+
+func TestGetLogsSince(t *testing.T) {
+	t.Parallel()
+
+	since := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/namespaces/test/pods/pod-0/log", r.URL.Path)
+		assert.Equal(t, "mongod", r.URL.Query().Get("container"))
+		assert.Equal(t, "2021-03-04T05:06:07Z", r.URL.Query().Get("sinceTime"))
+		assert.Empty(t, r.URL.Query().Get("tailLines"))
+		_, _ = w.Write([]byte("line 1\nline 2"))
+	}))
+	defer srv.Close()
+
+	clientset, err := kubernetes.NewForConfig(&rest.Config{Host: srv.URL})
+	require.NoError(t, err)
+	c := &Client{clientset: clientset, namespace: "test"}
+
+	logs, err := c.GetLogsSince(context.Background(), "pod-0", "mongod", since)
+	require.NoError(t, err)
+	assert.Equal(t, "line 1\nline 2", logs)
+}
+
+// fakeAPIServer is a minimal Kubernetes API server keeping config maps of test namespace in memory.
+// Config map named "invalid" is rejected.
+type fakeAPIServer struct {
+	*httptest.Server
+
+	m          sync.Mutex
+	configMaps map[string][]byte
+	requests   []string
+}
+
+func newFakeAPIServer(t *testing.T) *fakeAPIServer {
+	t.Helper()
+
+	s := &fakeAPIServer{configMaps: make(map[string][]byte)}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *fakeAPIServer) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	const configMapsPath = "/api/v1/namespaces/test/configmaps"
+
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	status := func(code int, reason metav1.StatusReason) {
+		w.WriteHeader(code)
+		_ = json.NewEncoder(w).Encode(&metav1.Status{
+			TypeMeta: metav1.TypeMeta{Kind: "Status", APIVersion: "v1"},
+			Status:   metav1.StatusFailure,
+			Reason:   reason,
+			Code:     int32(code),
+		})
+	}
+
+	switch r.URL.Path {
+	case "/api":
+		_, _ = w.Write([]byte(`{"kind":"APIVersions","versions":["v1"]}`))
+		return
+	case "/apis":
+		_, _ = w.Write([]byte(`{"kind":"APIGroupList","groups":[]}`))
+		return
+	case "/api/v1":
+		_, _ = w.Write([]byte(`{"kind":"APIResourceList","groupVersion":"v1","resources":[{"name":"configmaps",` +
+			`"singularName":"configmap","namespaced":true,"kind":"ConfigMap","verbs":["create","delete","get","update"]}]}`))
+		return
+	}
+
+	dryRun := r.URL.Query().Get("dryRun")
+	s.requests = append(s.requests, strings.TrimSpace(r.Method+" "+path.Base(r.URL.Path)+" "+dryRun))
+	name := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, configMapsPath), "/")
+	switch {
+	case r.Method == http.MethodGet:
+		if body, ok := s.configMaps[name]; ok {
+			_, _ = w.Write(body)
+			return
+		}
+		status(http.StatusNotFound, metav1.StatusReasonNotFound)
+	case r.Method == http.MethodDelete:
+		delete(s.configMaps, name)
+		_, _ = w.Write([]byte(`{"kind":"Status","apiVersion":"v1","status":"Success"}`))
+	case r.Method == http.MethodPost || r.Method == http.MethodPut:
+		body, _ := ioutil.ReadAll(r.Body)
+		var obj metav1.PartialObjectMetadata
+		_ = json.Unmarshal(body, &obj)
+		if obj.Name == "invalid" {
+			status(http.StatusUnprocessableEntity, metav1.StatusReasonInvalid)
+			return
+		}
+		if dryRun != metav1.DryRunAll {
+			s.configMaps[obj.Name] = body
+		}
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusCreated)
+		}
+		_, _ = w.Write(body)
+	default:
+		status(http.StatusMethodNotAllowed, metav1.StatusReasonMethodNotAllowed)
+	}
+}
+
+// client returns a client of the server using test namespace.
+func (s *fakeAPIServer) client(t *testing.T) *Client {
+	t.Helper()
+
+	config := &rest.Config{Host: s.URL}
+	clientset, err := kubernetes.NewForConfig(config)
+	require.NoError(t, err)
+	return &Client{clientset: clientset, restConfig: config, namespace: "test", mu: new(sync.Mutex)}
+}
+
+// names returns sorted names of stored config maps.
+func (s *fakeAPIServer) names() []string {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	names := make([]string, 0, len(s.configMaps))
+	for name := range s.configMaps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// requestsLog returns received object requests in "METHOD name dryRun" form.
+func (s *fakeAPIServer) requestsLog() []string {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	return append([]string(nil), s.requests...)
+}
+
+func configMapsManifest(names ...string) []byte {
+	docs := make([]string, 0, len(names))
+	for _, name := range names {
+		docs = append(docs, "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: "+name+"\n")
+	}
+	return []byte(strings.Join(docs, "---\n"))
+}
+
+func TestApplyFileDryRun(t *testing.T) {
+	t.Parallel()
+
+	s := newFakeAPIServer(t)
+	c := s.client(t)
+
+	err := c.ApplyFileDryRun(context.Background(), configMapsManifest("a", "invalid", "b"))
+	var fileErr *FileError
+	require.ErrorAs(t, err, &fileErr)
+	require.Len(t, fileErr.Errors, 1)
+	assert.Equal(t, "invalid", fileErr.Errors[0].Name)
+
+	assert.Empty(t, s.names(), "dry run shouldn't persist objects")
+	assert.Equal(t, []string{
+		"GET a", "POST configmaps All",
+		"GET invalid", "POST configmaps All",
+		"GET b", "POST configmaps All",
+	}, s.requestsLog())
+}
+
+func TestApplyFileRollbackOnError(t *testing.T) {
+	t.Parallel()
+
+	s := newFakeAPIServer(t)
+	c := s.client(t)
+	require.NoError(t, c.ApplyFile(context.Background(), configMapsManifest("b"), StopOnError))
+
+	err := c.ApplyFile(context.Background(), configMapsManifest("a", "b", "invalid", "c"), RollbackOnError)
+	var fileErr *FileError
+	require.ErrorAs(t, err, &fileErr)
+	require.Len(t, fileErr.Errors, 1)
+	assert.Equal(t, "invalid", fileErr.Errors[0].Name)
+
+	// Created object is deleted, existing one is kept, the ones after failed are not applied.
+	assert.Equal(t, []string{"b"}, s.names())
+	assert.Contains(t, s.requestsLog(), "DELETE a")
+	assert.NotContains(t, s.requestsLog(), "GET c")
+}
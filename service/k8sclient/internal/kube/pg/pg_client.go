@@ -0,0 +1,141 @@
+// dbaas-controller
+// Copyright (C) 2020 Percona LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+// Package pg provides Percona Distribution for PostgreSQL client for kubernetes.
+package pg
+
+import (
+	"context"
+	"sync"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	// PGKind is a kind of Percona Distribution for PostgreSQL custom resource.
+	PGKind    = "PerconaPGCluster"
+	pgAPIKind = "perconapgclusters"
+	// CRDName is a name of custom resource definition of PostgreSQL clusters.
+	CRDName = pgAPIKind + ".pg.percona.com"
+)
+
+// PerconaPGClusterClientInterface provides access to PostgreSQL clusters in a namespace.
+type PerconaPGClusterClientInterface interface {
+	PGClusters(namespace string) PerconaPGClusterInterface
+}
+
+// PerconaPGClusterClient is a REST client of PostgreSQL cluster custom resources.
+type PerconaPGClusterClient struct {
+	restClient rest.Interface
+}
+
+var addToScheme sync.Once
+
+// NewForConfig creates a new client for the given REST config
+// and registers PostgreSQL cluster types in the client-go scheme.
+func NewForConfig(c *rest.Config) (*PerconaPGClusterClient, error) {
+	config := *c
+	config.ContentConfig.GroupVersion = &SchemeGroupVersion
+	config.APIPath = "/apis"
+	config.NegotiatedSerializer = scheme.Codecs.WithoutConversion()
+	config.UserAgent = rest.DefaultKubernetesUserAgent()
+
+	addToScheme.Do(func() {
+		scheme.Scheme.AddKnownTypes(SchemeGroupVersion, &PerconaPGCluster{}, &PerconaPGClusterList{})
+		metav1.AddToGroupVersion(scheme.Scheme, SchemeGroupVersion)
+	})
+
+	client, err := rest.RESTClientFor(&config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PerconaPGClusterClient{restClient: client}, nil
+}
+
+// PGClusters returns interface to PostgreSQL clusters in given namespace.
+func (c *PerconaPGClusterClient) PGClusters(namespace string) PerconaPGClusterInterface {
+	return &pgClient{
+		restClient: c.restClient,
+		namespace:  namespace,
+	}
+}
+
+// PerconaPGClusterInterface has methods to work with PostgreSQL cluster custom resources.
+type PerconaPGClusterInterface interface {
+	List(ctx context.Context, opts metav1.ListOptions) (*PerconaPGClusterList, error)
+	Get(ctx context.Context, name string, options metav1.GetOptions) (*PerconaPGCluster, error)
+	Patch(context.Context, string, types.PatchType, []byte, metav1.PatchOptions) (*PerconaPGCluster, error)
+	Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error)
+}
+
+type pgClient struct {
+	restClient rest.Interface
+	namespace  string
+}
+
+func (c *pgClient) List(ctx context.Context, opts metav1.ListOptions) (*PerconaPGClusterList, error) {
+	result := new(PerconaPGClusterList)
+	err := c.restClient.
+		Get().
+		Namespace(c.namespace).
+		Resource(pgAPIKind).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Do(ctx).
+		Into(result)
+	return result, err
+}
+
+func (c *pgClient) Get(ctx context.Context, name string, opts metav1.GetOptions) (*PerconaPGCluster, error) {
+	result := new(PerconaPGCluster)
+	err := c.restClient.
+		Get().
+		Namespace(c.namespace).
+		Resource(pgAPIKind).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Name(name).
+		Do(ctx).
+		Into(result)
+	return result, err
+}
+
+func (c *pgClient) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions) (*PerconaPGCluster, error) {
+	result := new(PerconaPGCluster)
+	err := c.restClient.
+		Patch(pt).
+		Namespace(c.namespace).
+		Resource(pgAPIKind).
+		Name(name).
+		Body(data).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Do(ctx).
+		Into(result)
+	return result, err
+}
+
+func (c *pgClient) Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+	opts.Watch = true
+	return c.restClient.
+		Get().
+		Namespace(c.namespace).
+		Resource(pgAPIKind).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Watch(ctx)
+}
@@ -0,0 +1,238 @@
+// dbaas-controller
+// Copyright (C) 2020 Percona LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package pg
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// SchemeGroupVersion is group version used to register Percona Distribution for PostgreSQL objects.
+var SchemeGroupVersion = schema.GroupVersion{Group: "pg.percona.com", Version: "v1"} //nolint:gochecknoglobals
+
+// Cluster states reported by the operator.
+const (
+	AppStateCreated     = "pgcluster Created"
+	AppStateInitialized = "pgcluster Initialized"
+	AppStateShutdown    = "pgcluster Shutdown"
+)
+
+// PerconaPGCluster is the Schema for the perconapgclusters API.
+// Only the subset of fields used by dbaas-controller is defined.
+type PerconaPGCluster struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   PerconaPGClusterSpec   `json:"spec,omitempty"`
+	Status PerconaPGClusterStatus `json:"status,omitempty"`
+}
+
+// PerconaPGClusterList contains a list of PerconaPGCluster.
+type PerconaPGClusterList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PerconaPGCluster `json:"items"`
+}
+
+// PerconaPGClusterSpec defines the desired state of PerconaPGCluster.
+type PerconaPGClusterSpec struct {
+	Database    string      `json:"database,omitempty"`
+	Port        string      `json:"port,omitempty"`
+	User        string      `json:"user,omitempty"`
+	SecretsName string      `json:"secretsName,omitempty"`
+	Pause       bool        `json:"pause"`
+	PGPrimary   *PGPrimary  `json:"pgPrimary,omitempty"`
+	PGReplicas  *PGReplicas `json:"pgReplicas,omitempty"`
+	PGBouncer   *PGBouncer  `json:"pgBouncer,omitempty"`
+	Backup      *Backup     `json:"backup,omitempty"`
+	PMM         *PMMSpec    `json:"pmm,omitempty"`
+}
+
+// PGPrimary defines primary PostgreSQL instance.
+type PGPrimary struct {
+	Image      string                      `json:"image"`
+	Resources  corev1.ResourceRequirements `json:"resources,omitempty"`
+	VolumeSpec *VolumeSpec                 `json:"volumeSpec,omitempty"`
+	Expose     Expose                      `json:"expose,omitempty"`
+}
+
+// PGReplicas defines PostgreSQL replicas.
+type PGReplicas struct {
+	HotStandby *HotStandby `json:"hotStandby,omitempty"`
+}
+
+// HotStandby defines hot standby replicas.
+type HotStandby struct {
+	Size       int32                       `json:"size"`
+	Resources  corev1.ResourceRequirements `json:"resources,omitempty"`
+	VolumeSpec *VolumeSpec                 `json:"volumeSpec,omitempty"`
+	Expose     Expose                      `json:"expose,omitempty"`
+}
+
+// PGBouncer defines pgBouncer connection pooler.
+type PGBouncer struct {
+	Image     string                      `json:"image"`
+	Size      int32                       `json:"size"`
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+	Expose    Expose                      `json:"expose,omitempty"`
+}
+
+// Backup defines pgBackRest configuration.
+type Backup struct {
+	Image             string      `json:"image,omitempty"`
+	BackrestRepoImage string      `json:"backrestRepoImage,omitempty"`
+	VolumeSpec        *VolumeSpec `json:"volumeSpec,omitempty"`
+}
+
+// PMMSpec defines PMM client configuration.
+type PMMSpec struct {
+	Enabled    bool                        `json:"enabled"`
+	Image      string                      `json:"image,omitempty"`
+	ServerHost string                      `json:"serverHost,omitempty"`
+	ServerUser string                      `json:"serverUser,omitempty"`
+	PMMSecret  string                      `json:"pmmSecret,omitempty"`
+	Resources  corev1.ResourceRequirements `json:"resources,omitempty"`
+}
+
+// VolumeSpec defines storage of PostgreSQL instances.
+type VolumeSpec struct {
+	Size         string `json:"size"`
+	AccessMode   string `json:"accessmode"`
+	StorageType  string `json:"storagetype"`
+	StorageClass string `json:"storageclass,omitempty"`
+}
+
+// Expose defines how service is exposed.
+type Expose struct {
+	ServiceType corev1.ServiceType `json:"serviceType,omitempty"`
+}
+
+// PerconaPGClusterStatus defines the observed state of PerconaPGCluster.
+type PerconaPGClusterStatus struct {
+	PGCluster  PGStatus            `json:"pgCluster,omitempty"`
+	PGReplicas map[string]PGStatus `json:"pgReplicas,omitempty"`
+	Size       int32               `json:"size,omitempty"`
+}
+
+// PGStatus contains state of cluster component.
+type PGStatus struct {
+	State   string `json:"state,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// DeepCopyInto copies the receiver into out.
+func (in *PerconaPGCluster) DeepCopyInto(out *PerconaPGCluster) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy creates a new PerconaPGCluster.
+func (in *PerconaPGCluster) DeepCopy() *PerconaPGCluster {
+	if in == nil {
+		return nil
+	}
+	out := new(PerconaPGCluster)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject implements runtime.Object.
+func (in *PerconaPGCluster) DeepCopyObject() runtime.Object {
+	return in.DeepCopy()
+}
+
+// DeepCopyInto copies the receiver into out.
+func (in *PerconaPGClusterList) DeepCopyInto(out *PerconaPGClusterList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		out.Items = make([]PerconaPGCluster, len(in.Items))
+		for i := range in.Items {
+			in.Items[i].DeepCopyInto(&out.Items[i])
+		}
+	}
+}
+
+// DeepCopyObject implements runtime.Object.
+func (in *PerconaPGClusterList) DeepCopyObject() runtime.Object {
+	if in == nil {
+		return nil
+	}
+	out := new(PerconaPGClusterList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out.
+func (in *PerconaPGClusterSpec) DeepCopyInto(out *PerconaPGClusterSpec) {
+	*out = *in
+	if in.PGPrimary != nil {
+		out.PGPrimary = new(PGPrimary)
+		*out.PGPrimary = *in.PGPrimary
+		in.PGPrimary.Resources.DeepCopyInto(&out.PGPrimary.Resources)
+		out.PGPrimary.VolumeSpec = in.PGPrimary.VolumeSpec.deepCopy()
+	}
+	if in.PGReplicas != nil {
+		out.PGReplicas = new(PGReplicas)
+		if in.PGReplicas.HotStandby != nil {
+			out.PGReplicas.HotStandby = new(HotStandby)
+			*out.PGReplicas.HotStandby = *in.PGReplicas.HotStandby
+			in.PGReplicas.HotStandby.Resources.DeepCopyInto(&out.PGReplicas.HotStandby.Resources)
+			out.PGReplicas.HotStandby.VolumeSpec = in.PGReplicas.HotStandby.VolumeSpec.deepCopy()
+		}
+	}
+	if in.PGBouncer != nil {
+		out.PGBouncer = new(PGBouncer)
+		*out.PGBouncer = *in.PGBouncer
+		in.PGBouncer.Resources.DeepCopyInto(&out.PGBouncer.Resources)
+	}
+	if in.Backup != nil {
+		out.Backup = new(Backup)
+		*out.Backup = *in.Backup
+		out.Backup.VolumeSpec = in.Backup.VolumeSpec.deepCopy()
+	}
+	if in.PMM != nil {
+		out.PMM = new(PMMSpec)
+		*out.PMM = *in.PMM
+		in.PMM.Resources.DeepCopyInto(&out.PMM.Resources)
+	}
+}
+
+// DeepCopyInto copies the receiver into out.
+func (in *PerconaPGClusterStatus) DeepCopyInto(out *PerconaPGClusterStatus) {
+	*out = *in
+	if in.PGReplicas != nil {
+		out.PGReplicas = make(map[string]PGStatus, len(in.PGReplicas))
+		for k, v := range in.PGReplicas {
+			out.PGReplicas[k] = v
+		}
+	}
+}
+
+func (in *VolumeSpec) deepCopy() *VolumeSpec {
+	if in == nil {
+		return nil
+	}
+	out := *in
+	return &out
+}
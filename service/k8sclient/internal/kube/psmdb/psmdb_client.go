@@ -32,6 +32,7 @@ import (
 const (
 	PSMDBKind    = "PerconaServerMongoDB"
 	psmdbAPIKind = "perconaservermongodbs"
+	CRDName      = psmdbAPIKind + ".psmdb.percona.com"
 )
 
 type PerconaServerMongoDBClientInterface interface {
@@ -0,0 +1,56 @@
+// dbaas-controller
+// Copyright (C) 2020 Percona LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package pxc
+
+import (
+	"context"
+
+	pxcv1 "github.com/percona/percona-xtradb-cluster-operator/pkg/apis/pxc/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+)
+
+const backupAPIKind = "perconaxtradbclusterbackups"
+
+func (c *PerconaXtraDBClusterClient) PXCBackups(namespace string) PerconaXtraDBClusterBackupInterface {
+	return &pxcBackupClient{
+		restClient: c.restClient,
+		namespace:  namespace,
+	}
+}
+
+type PerconaXtraDBClusterBackupInterface interface {
+	List(ctx context.Context, opts metav1.ListOptions) (*pxcv1.PerconaXtraDBClusterBackupList, error)
+}
+
+type pxcBackupClient struct {
+	restClient rest.Interface
+	namespace  string
+}
+
+func (c *pxcBackupClient) List(ctx context.Context, opts metav1.ListOptions) (*pxcv1.PerconaXtraDBClusterBackupList, error) {
+	result := new(pxcv1.PerconaXtraDBClusterBackupList)
+	err := c.restClient.
+		Get().
+		Namespace(c.namespace).
+		Resource(backupAPIKind).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Do(ctx).
+		Into(result)
+	return result, err
+}
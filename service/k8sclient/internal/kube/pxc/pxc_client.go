@@ -32,10 +32,12 @@ import (
 const (
 	PXCKind = "PerconaXtraDBCluster"
 	apiKind = "perconaxtradbclusters"
+	CRDName = apiKind + ".pxc.percona.com"
 )
 
 type PerconaXtraDBClusterClientInterface interface {
 	PXCClusters(namespace string) PerconaXtraDBClusterInterface
+	PXCBackups(namespace string) PerconaXtraDBClusterBackupInterface
 }
 
 type PerconaXtraDBClusterClient struct {
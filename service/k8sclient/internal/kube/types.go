@@ -18,47 +18,109 @@ package kube
 import (
 	psmdbv1 "github.com/percona/percona-server-mongodb-operator/pkg/apis/psmdb/v1"
 	pxcv1 "github.com/percona/percona-xtradb-cluster-operator/pkg/apis/pxc/v1"
+
+	"github.com/percona-platform/dbaas-controller/service/k8sclient/internal/kube/pg"
+	"github.com/percona-platform/dbaas-controller/service/k8sclient/internal/kube/psmdb"
+	"github.com/percona-platform/dbaas-controller/service/k8sclient/internal/kube/pxc"
 )
 
-type DBCluster struct {
-	State          string
-	Pause          bool
-	Name           string
-	CRImage        string
-	ContainerNames []string
-	PodLabels      []string
+// DBCluster represents a database cluster managed by one of the supported operators.
+// Adding a new engine requires only a constructor returning DBCluster.
+type DBCluster interface {
+	// CRDName returns name of the custom resource definition of the cluster.
+	CRDName() string
+	// Name returns name of the cluster.
+	Name() string
+	// State returns state of the cluster as reported by the operator.
+	State() string
+	// Paused returns true if the cluster was requested to be paused.
+	Paused() bool
+	// DatabaseImage returns database image set in the custom resource.
+	DatabaseImage() string
+	// DatabasePodLabels returns label selectors of the database pods.
+	DatabasePodLabels() []string
+	// DatabaseContainerNames returns names of the database containers.
+	DatabaseContainerNames() []string
+}
+
+type dbCluster struct {
+	crdName        string
+	state          string
+	pause          bool
+	name           string
+	crImage        string
+	containerNames []string
+	podLabels      []string
 }
 
+func (db *dbCluster) CRDName() string                  { return db.crdName }
+func (db *dbCluster) Name() string                     { return db.name }
+func (db *dbCluster) State() string                    { return db.state }
+func (db *dbCluster) Paused() bool                     { return db.pause }
+func (db *dbCluster) DatabaseImage() string            { return db.crImage }
+func (db *dbCluster) DatabasePodLabels() []string      { return db.podLabels }
+func (db *dbCluster) DatabaseContainerNames() []string { return db.containerNames }
+
 func NewDBClusterInfoFromPXC(cluster *pxcv1.PerconaXtraDBCluster) DBCluster {
 	if cluster == nil || cluster.Spec.PXC == nil {
-		return DBCluster{
-			State: string(pxcv1.AppStateUnknown),
+		return &dbCluster{
+			crdName: pxc.CRDName,
+			state:   string(pxcv1.AppStateUnknown),
 		}
 	}
-	db := DBCluster{
-		CRImage:        cluster.Spec.PXC.Image,
-		State:          string(cluster.Status.Status),
-		Pause:          cluster.Spec.Pause,
-		Name:           cluster.Name,
-		ContainerNames: []string{"pxc"},
+	return &dbCluster{
+		crdName:        pxc.CRDName,
+		crImage:        cluster.Spec.PXC.Image,
+		state:          string(cluster.Status.Status),
+		pause:          cluster.Spec.Pause,
+		name:           cluster.Name,
+		containerNames: []string{"pxc"},
+		podLabels:      []string{"app.kubernetes.io/instance=" + cluster.Name, "app.kubernetes.io/component=pxc"},
 	}
-	db.PodLabels = []string{"app.kubernetes.io/instance=" + db.Name, "app.kubernetes.io/component=pxc"}
-	return db
 }
 
 func NewDBClusterInfoFromPSMDB(cluster *psmdbv1.PerconaServerMongoDB) DBCluster {
 	if cluster == nil || cluster == new(psmdbv1.PerconaServerMongoDB) || cluster.Status.State == "" {
-		return DBCluster{
-			State: string(pxcv1.AppStateUnknown),
+		return &dbCluster{
+			crdName: psmdb.CRDName,
+			state:   string(pxcv1.AppStateUnknown),
+		}
+	}
+	return &dbCluster{
+		crdName:        psmdb.CRDName,
+		crImage:        cluster.Spec.Image,
+		state:          string(cluster.Status.State),
+		pause:          cluster.Spec.Pause,
+		name:           cluster.Name,
+		containerNames: []string{"mongos", "mongod"},
+		podLabels:      []string{"app.kubernetes.io/instance=" + cluster.Name, "app.kubernetes.io/part-of=percona-server-mongodb"},
+	}
+}
+
+func NewDBClusterInfoFromPG(cluster *pg.PerconaPGCluster) DBCluster {
+	if cluster == nil || cluster.Spec.PGPrimary == nil || cluster.Status.PGCluster.State == "" {
+		return &dbCluster{
+			crdName: pg.CRDName,
+			state:   string(pxcv1.AppStateUnknown),
 		}
 	}
-	db := DBCluster{
-		CRImage:        cluster.Spec.Image,
-		State:          string(cluster.Status.State),
-		Pause:          cluster.Spec.Pause,
-		Name:           cluster.Name,
-		ContainerNames: []string{"mongos", "mongod"},
+	// PostgreSQL operator reports its own states, translate them to the ones used by other operators.
+	var state pxcv1.AppState
+	switch cluster.Status.PGCluster.State {
+	case pg.AppStateInitialized:
+		state = pxcv1.AppStateReady
+	case pg.AppStateShutdown:
+		state = pxcv1.AppStatePaused
+	default:
+		state = pxcv1.AppStateInit
+	}
+	return &dbCluster{
+		crdName:        pg.CRDName,
+		crImage:        cluster.Spec.PGPrimary.Image,
+		state:          string(state),
+		pause:          cluster.Spec.Pause,
+		name:           cluster.Name,
+		containerNames: []string{"database"},
+		podLabels:      []string{"pg-cluster=" + cluster.Name, "pgo-pg-database=true"},
 	}
-	db.PodLabels = []string{"app.kubernetes.io/instance=" + db.Name, "app.kubernetes.io/part-of=percona-server-mongodb"}
-	return db
 }
@@ -18,6 +18,7 @@ package kubectl
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/pkg/errors"
 )
@@ -26,16 +27,29 @@ import (
 // inside Kubernetes cluster.
 var ErrNotFound error = errors.New("resource was not found in Kubernetes cluster")
 
-type kubeCtlError struct {
-	err    error
-	cmd    string
-	stderr string
+// KubectlError is returned when kubectl command fails.
+type KubectlError struct {
+	// ExitCode is kubectl exit code, -1 if kubectl was not started or was killed by signal.
+	ExitCode int
+	// Stderr is kubectl standard error output.
+	Stderr string
+	// Args are kubectl command and its arguments.
+	Args []string
+
+	err error
+}
+
+// Error implements error interface.
+func (e *KubectlError) Error() string {
+	return fmt.Sprintf("%s\ncmd: %s\nstderr: %s", e.err, strings.Join(e.Args, " "), e.Stderr)
 }
 
-func (e *kubeCtlError) Error() string {
-	return fmt.Sprintf("%s\ncmd: %s\nstderr: %s", e.err, e.cmd, e.stderr)
+// Cause returns underlying error for github.com/pkg/errors.
+func (e *KubectlError) Cause() error {
+	return e.err
 }
 
-// TODO Cause, Unwrap methods?
-// TODO Tests
-// https://jira.percona.com/browse/PMM-6349
+// Unwrap returns underlying error for errors.Is and errors.As.
+func (e *KubectlError) Unwrap() error {
+	return e.err
+}
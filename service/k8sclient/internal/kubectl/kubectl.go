@@ -302,6 +302,22 @@ func (k *KubeCtl) Delete(ctx context.Context, res interface{}) error {
 	return err
 }
 
+// Impersonate makes kubectl act as given user and groups.
+func (k *KubeCtl) Impersonate(user string, groups []string) {
+	k.cmd = append(k.cmd, impersonationArgs(user, groups)...)
+}
+
+func impersonationArgs(user string, groups []string) []string {
+	args := make([]string, 0, len(groups)+1)
+	if user != "" {
+		args = append(args, fmt.Sprintf("--as=%s", user))
+	}
+	for _, group := range groups {
+		args = append(args, fmt.Sprintf("--as-group=%s", group))
+	}
+	return args
+}
+
 // Run wraps func run.
 func (k *KubeCtl) Run(ctx context.Context, args []string, stdin interface{}) ([]byte, error) {
 	out, err := run(ctx, k.cmd, args, stdin)
@@ -358,10 +374,16 @@ func run(ctx context.Context, kubectlCmd []string, args []string, stdin interfac
 			l.Warn(errOutput)
 			err = ErrNotFound
 		} else {
-			err = &kubeCtlError{
-				err:    errors.WithStack(err),
-				cmd:    argsString,
-				stderr: errOutput,
+			exitCode := -1
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) {
+				exitCode = exitErr.ExitCode()
+			}
+			err = &KubectlError{
+				ExitCode: exitCode,
+				Stderr:   errOutput,
+				Args:     args,
+				err:      errors.WithStack(err),
 			}
 		}
 	}
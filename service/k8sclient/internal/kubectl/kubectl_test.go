@@ -24,6 +24,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -102,6 +103,26 @@ const kubernetsVersions = `
 }
 `
 
+func TestRunError(t *testing.T) {
+	t.Parallel()
+
+	_, err := run(context.Background(), []string{"sh", "-c"}, []string{"echo failed >&2; exit 3"}, nil)
+	var kubectlErr *KubectlError
+	require.True(t, errors.As(err, &kubectlErr))
+	assert.Equal(t, 3, kubectlErr.ExitCode)
+	assert.Equal(t, "failed\n", kubectlErr.Stderr)
+	assert.Equal(t, []string{"sh", "-c", "echo failed >&2; exit 3"}, kubectlErr.Args)
+}
+
+func TestImpersonate(t *testing.T) {
+	t.Parallel()
+
+	k := &KubeCtl{cmd: []string{"kubectl", "--kubeconfig=/tmp/config"}}
+	k.Impersonate("jane", []string{"dev", "ops"})
+	assert.Equal(t, []string{"kubectl", "--kubeconfig=/tmp/config", "--as=jane", "--as-group=dev", "--as-group=ops"}, k.cmd)
+	assert.Empty(t, impersonationArgs("", nil))
+}
+
 func TestSelectCorrectKubectlVersions(t *testing.T) {
 	t.Parallel()
 	t.Run("basic", func(t *testing.T) {
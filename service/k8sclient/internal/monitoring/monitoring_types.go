@@ -38,7 +38,7 @@ type VMAgentRemoteWriteSpec struct {
 	// URL of the endpoint to send samples to.
 	URL string `json:"url"`
 	// BasicAuth allow an endpoint to authenticate over basic authentication
-	BasicAuth *BasicAuth `json:"basicAuth"`
+	BasicAuth *BasicAuth `json:"basicAuth,omitempty"`
 	// TLSConfig describes tls configuration for remote write target.
 	TLSConfig *TLSConfig `json:"tlsConfig,omitempty"`
 }
@@ -54,6 +54,7 @@ type VMAgentSpec struct {
 	StaticScrapeSelector           *metav1.LabelSelector        `json:"staticScrapeSelector"`
 	StaticScrapeNamespaceSelector  *metav1.LabelSelector        `json:"staticScrapeNamespaceSelector"`
 	ReplicaCount                   int                          `json:"replicaCount"`
+	ScrapeInterval                 string                       `json:"scrapeInterval,omitempty"`
 	Resources                      *corev1.ResourceRequirements `json:"resources"`
 	ExtraArgs                      map[string]string            `json:"extraArgs"`
 	RemoteWrite                    []VMAgentRemoteWriteSpec     `json:"remoteWrite"`
@@ -73,17 +74,25 @@ type VMAgent struct {
 	Spec VMAgentSpec `json:"spec"`
 }
 
+// SecretOrConfigMap allows to specify data as a Secret or ConfigMap. Fields are mutually exclusive.
+type SecretOrConfigMap struct {
+	// Secret containing data to use for the targets.
+	Secret *corev1.SecretKeySelector `json:"secret,omitempty"`
+	// ConfigMap containing data to use for the targets.
+	ConfigMap *corev1.ConfigMapKeySelector `json:"configMap,omitempty"`
+}
+
 // TLSConfig specifies TLSConfig configuration parameters.
 type TLSConfig struct {
 	// Path to the CA cert in the container to use for the targets.
 	CAFile string `json:"caFile,omitempty"`
 	// Stuct containing the CA cert to use for the targets.
-	CA *corev1.SecretKeySelector `json:"ca,omitempty"`
+	CA *SecretOrConfigMap `json:"ca,omitempty"`
 
 	// Path to the client cert file in the container for the targets.
 	CertFile string `json:"certFile,omitempty"`
 	// Struct containing the client cert file for the targets.
-	Cert *corev1.SecretKeySelector `json:"cert,omitempty"`
+	Cert *SecretOrConfigMap `json:"cert,omitempty"`
 
 	// Path to the client key file in the container for the targets.
 	KeyFile string `json:"keyFile,omitempty"`
@@ -44,17 +44,42 @@ func (in *BasicAuth) DeepCopy() *BasicAuth {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretOrConfigMap) DeepCopyInto(out *SecretOrConfigMap) {
+	*out = *in
+	if in.Secret != nil {
+		in, out := &in.Secret, &out.Secret
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ConfigMap != nil {
+		in, out := &in.ConfigMap, &out.ConfigMap
+		*out = new(corev1.ConfigMapKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretOrConfigMap.
+func (in *SecretOrConfigMap) DeepCopy() *SecretOrConfigMap {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretOrConfigMap)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TLSConfig) DeepCopyInto(out *TLSConfig) {
 	*out = *in
 	if in.CA != nil {
 		in, out := &in.CA, &out.CA
-		*out = new(corev1.SecretKeySelector)
+		*out = new(SecretOrConfigMap)
 		(*in).DeepCopyInto(*out)
 	}
 	if in.Cert != nil {
 		in, out := &in.Cert, &out.Cert
-		*out = new(corev1.SecretKeySelector)
+		*out = new(SecretOrConfigMap)
 		(*in).DeepCopyInto(*out)
 	}
 	if in.KeySecret != nil {
@@ -20,30 +20,48 @@ package k8sclient
 import (
 	"context"
 	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"math"
+	"net"
 	"net/http"
+	"net/url"
 	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/AlekSi/pointer"
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/google/uuid"
 	goversion "github.com/hashicorp/go-version"
+	"github.com/percona/percona-backup-mongodb/pbm"
 	psmdbv1 "github.com/percona/percona-server-mongodb-operator/pkg/apis/psmdb/v1"
 	pxcv1 "github.com/percona/percona-xtradb-cluster-operator/pkg/apis/pxc/v1"
 	pmmversion "github.com/percona/pmm/version"
 	"github.com/pkg/errors"
+	"golang.org/x/sync/errgroup"
 	"gopkg.in/yaml.v2"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
-	"k8s.io/client-go/kubernetes"
-	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
 
 	dbaascontroller "github.com/percona-platform/dbaas-controller"
 	"github.com/percona-platform/dbaas-controller/service/k8sclient/common"
@@ -82,8 +100,10 @@ const (
 )
 
 const (
-	k8sAPIVersion     = "v1"
-	k8sMetaKindSecret = "Secret"
+	k8sAPIVersion        = "v1"
+	k8sMetaKindSecret    = "Secret"
+	k8sMetaKindConfigMap = "ConfigMap"
+	k8sMetaKindPod       = "Pod"
 
 	pxcBackupImageTemplate          = "percona/percona-xtradb-cluster-operator:%s-pxc8.0-backup"
 	pxcDefaultImage                 = "percona/percona-xtradb-cluster:8.0.20-11.1"
@@ -94,21 +114,84 @@ const (
 	pxcHAProxyDefaultImageTemplate  = "percona/percona-xtradb-cluster-operator:%s-haproxy"
 	pxcSecretNameTmpl               = "dbaas-%s-pxc-secrets" //nolint:gosec
 	pxcInternalSecretTmpl           = "internal-%s"
-
-	psmdbBackupImageTemplate = "percona/percona-server-mongodb-operator:%s-backup"
-	psmdbDefaultImage        = "percona/percona-server-mongodb:4.2.8-8"
-	psmdbAPINamespace        = "psmdb.percona.com"
-	psmdbAPIVersionTemplate  = psmdbAPINamespace + "/v%s"
-	psmdbSecretNameTmpl      = "dbaas-%s-psmdb-secrets" //nolint:gosec
-	stabePMMClientImage      = "percona/pmm-client:2"
+	pxcOperatorDeploymentName       = "percona-xtradb-cluster-operator"
+	pxcProxySQLPVCFinalizer         = "delete-proxysql-pvc"
+	pxcPVCFinalizer                 = "delete-pxc-pvc"
+	// pxcPort is a port of PXC proxies, it can't be changed in PXC custom resource.
+	pxcPort                 = 3306
+	forceDeleteTimeout      = 2 * time.Minute
+	waitForConditionTimeout = 5 * time.Minute
+
+	psmdbBackupImageTemplate     = "percona/percona-server-mongodb-operator:%s-backup"
+	psmdbDefaultImage            = "percona/percona-server-mongodb:4.2.8-8"
+	psmdbAPINamespace            = "psmdb.percona.com"
+	psmdbAPIVersionTemplate      = psmdbAPINamespace + "/v%s"
+	psmdbSecretNameTmpl          = "dbaas-%s-psmdb-secrets"    //nolint:gosec
+	psmdbEncryptionKeySecretTmpl = "%s-mongodb-encryption-key" //nolint:gosec
+	psmdbDefaultPort             = 27017
+	psmdbDefaultReplsetName      = "rs0"
+	psmdbDefaultConfigServerSize = 3
+	psmdbServiceDNSSuffixPrefix  = "svc."
+	psmdbOperatorDeploymentName  = "percona-server-mongodb-operator"
+	stabePMMClientImage          = "percona/pmm-client:2"
 
 	// Max size of volume for AWS Elastic Block Storage service is 16TiB.
-	maxVolumeSizeEBS uint64 = 16 * 1024 * 1024 * 1024 * 1024
-	pullPolicy              = common.PullIfNotPresent
-	pxcCRFile               = "/srv/dbaas/crs/pxc.cr.yml"
-	psmdbCRFile             = "/srv/dbaas/crs/psmdb.cr.yml"
+	maxVolumeSizeEBS      uint64 = 16 * 1024 * 1024 * 1024 * 1024
+	pullPolicy                   = common.PullIfNotPresent
+	defaultCRTemplatesDir        = "/srv/dbaas/crs"
+	crTemplatesDirEnv            = "DBAAS_CR_TEMPLATES_DIR"
+	caBundleFileEnv              = "DBAAS_CA_BUNDLE_FILE"
+	defaultS3CheckImage          = "amazon/aws-cli:2.15.0"
+	s3CheckTimeout               = 2 * time.Minute
+	s3CheckDeleteTimeout         = 30 * time.Second
+	crTemplatesConfigMap         = "dbaas-cr-templates"
+	pxcCRFile                    = "pxc.cr.yml"
+	psmdbCRFile                  = "psmdb.cr.yml"
+
+	pmmClientMemoryRequestBytes uint64 = 300 * 1000 * 1000
+	pmmClientCPURequestM        uint64 = 500
+	vmAgentMemoryRequestBytes   uint64 = 350 * 1024 * 1024
+	vmAgentMemoryLimitBytes     uint64 = 850 * 1024 * 1024
+	vmAgentCPURequestM          uint64 = 250
+	vmAgentCPULimitM            uint64 = 500
+)
+
+// listClustersConcurrency is a maximum number of clusters processed concurrently while listing clusters.
+const listClustersConcurrency = 8
+
+// applyRetryBackoff waits up to about 15 seconds for admission webhooks to become ready.
+var applyRetryBackoff = wait.Backoff{
+	Steps:    5,
+	Duration: time.Second,
+	Factor:   2,
+	Jitter:   0.1,
+}
+
+const (
+	// maxGitHubTagsPages limits number of requests to GitHub API while listing operator versions.
+	maxGitHubTagsPages = 10
+
+	// managedByLabel marks custom resources created by dbaas-controller to distinguish them from manually created ones.
+	managedByLabel = "dbaas.percona.com/managed-by"
+	managedByValue = "dbaas-controller"
+
+	// historyAnnotation keeps JSON list of operations made by dbaas-controller on custom resource.
+	historyAnnotation    = "dbaas.percona.com/history"
+	maxHistoryOperations = 20
+
+	defaultHTTPTimeout          = 5 * time.Second
+	defaultManifestFetchTimeout = 2 * time.Minute
+	// defaultHTTPMaxIdleConns lets back-to-back manifest and GitHub API requests reuse connections.
+	defaultHTTPMaxIdleConns    = 10
+	defaultHTTPIdleConnTimeout = 90 * time.Second
 )
 
+// DefaultLogTailLines is a default number of log lines returned by GetLogs.
+const DefaultLogTailLines = 3000
+
+// DatabasePodsSelector selects pods managed by PXC and PSMDB operators.
+const DatabasePodsSelector = "app.kubernetes.io/managed-by in (percona-xtradb-cluster-operator,percona-server-mongodb-operator)"
+
 // KubernetesClusterType represents kubernetes cluster type(eg: EKS, Minikube).
 type KubernetesClusterType uint8
 
@@ -118,8 +201,20 @@ const (
 	AmazonEKSClusterType
 	// MinikubeClusterType represents minikube Kubernetes cluster.
 	MinikubeClusterType
+	// GoogleGKEClusterType represents GKE cluster type.
+	GoogleGKEClusterType
+	// AzureAKSClusterType represents AKS cluster type.
+	AzureAKSClusterType
 )
 
+// internalLoadBalancerAnnotations are service annotations which make cloud providers create
+// load balancers reachable from the cluster's VPC only.
+var internalLoadBalancerAnnotations = map[KubernetesClusterType]map[string]string{ //nolint:gochecknoglobals
+	AmazonEKSClusterType: {"service.beta.kubernetes.io/aws-load-balancer-internal": "true"},
+	GoogleGKEClusterType: {"networking.gke.io/load-balancer-type": "Internal"},
+	AzureAKSClusterType:  {"service.beta.kubernetes.io/azure-load-balancer-internal": "true"},
+}
+
 // ContainerState describes container's state - waiting, running, terminated.
 type ContainerState string
 
@@ -145,6 +240,41 @@ type Operator struct {
 type Operators struct {
 	PXCOperatorVersion   string
 	PsmdbOperatorVersion string
+	// apiVersions contains all API versions served by Kubernetes API server.
+	apiVersions []string
+}
+
+// BackupStorageType is a type of backup storage.
+type BackupStorageType string
+
+const (
+	// BackupStorageFilesystem stores backups on a persistent volume.
+	BackupStorageFilesystem BackupStorageType = "filesystem"
+	// BackupStorageS3 stores backups in S3 compatible bucket.
+	BackupStorageS3 BackupStorageType = "s3"
+)
+
+// BackupStorage represents a storage cluster backups are written to.
+type BackupStorage struct {
+	Name string
+	Type BackupStorageType
+	// DiskSize is a size of filesystem storage volume, PXC disk size is used if empty.
+	DiskSize string
+	// Bucket, CredentialsSecret, Region and EndpointURL configure S3 storage.
+	Bucket            string
+	CredentialsSecret string
+	Region            string
+	EndpointURL       string
+}
+
+// BackupSchedule represents scheduled backups written to one of cluster backup storages.
+type BackupSchedule struct {
+	Name string
+	// Schedule is in cron format.
+	Schedule string
+	// Keep is a number of backups to keep.
+	Keep        int
+	StorageName string
 }
 
 // ComputeResources represents container computer resources requests or limits.
@@ -187,6 +317,10 @@ type PMM struct {
 	Login string
 	// PMM server admin password.
 	Password string
+	// PMM server API key, it's used instead of login and password by operators supporting it.
+	APIKey string
+	// Resources of pmm-client container, default requests are used if empty.
+	Resources *ComputeResources
 }
 
 // PXCParams contains all parameters required to create or update Percona XtraDB cluster.
@@ -201,6 +335,42 @@ type PXCParams struct {
 	ProxySQL          *ProxySQL
 	PMM               *PMM
 	HAProxy           *HAProxy
+	// BackupImage is an image of backup container, it's derived from operator version if empty.
+	BackupImage string
+	// BackupResources are resource limits of backup container, operator defaults are used if empty.
+	BackupResources *ComputeResources
+	// BackupServiceAccount is a service account of backup jobs, e.g. one bound to cloud IAM role for S3 access.
+	// Operator's service account is used if empty.
+	BackupServiceAccount string
+	// ExposeInternal makes exposed cluster reachable from the cluster's VPC only, it's supported for EKS, GKE and AKS.
+	ExposeInternal bool
+	// BackupStorages replace default filesystem backup storage if not empty.
+	BackupStorages []BackupStorage
+	// BackupSchedules replace default backup schedule if not empty.
+	// Each of them should reference one of BackupStorages, or the default storage if BackupStorages is empty.
+	BackupSchedules []BackupSchedule
+	// TemplateName is a name of template registered with RegisterTemplate to create cluster from.
+	// Template from CR templates directory is used if empty.
+	TemplateName string
+	// AllowUnsafe allows creating cluster of size which is prone to split-brain.
+	AllowUnsafe bool
+	// AllowUnsafeConfig disables operator's safety checks, e.g. of minimum cluster size.
+	// If nil, checks are disabled for clusters of less than 3 nodes only.
+	AllowUnsafeConfig *bool
+	// SecurityContext is set on database pods, operator defaults are used if nil.
+	SecurityContext *SecurityContext
+	// PriorityClassName is a name of existing priority class of database and proxy pods.
+	PriorityClassName string
+	// TerminationGracePeriodSeconds is a time given to PXC pods to shut down, operator default is used if nil.
+	TerminationGracePeriodSeconds *int64
+	// SkipCapacityCheck disables checking that requested pod resources fit on a node.
+	SkipCapacityCheck bool
+	// NodeSelector restricts cluster pods to nodes with matching labels, capacity check uses the same nodes.
+	NodeSelector map[string]string
+	// SchedulerName is a name of custom scheduler for cluster pods, default scheduler is used if empty.
+	SchedulerName string
+	// Overrides are deep-merged into generated custom resource before applying it.
+	Overrides map[string]interface{} `yaml:",omitempty"`
 }
 
 // Cluster contains common information related to cluster.
@@ -208,6 +378,13 @@ type Cluster struct {
 	Name string
 }
 
+// EncryptionParams contains data-at-rest encryption parameters of PSMDB cluster.
+type EncryptionParams struct {
+	Enabled bool
+	// CipherMode is either AES256-CBC or AES256-GCM, AES256-CBC is used if empty.
+	CipherMode string
+}
+
 // PSMDBParams contains all parameters required to create or update percona server for mongodb cluster.
 type PSMDBParams struct {
 	Name              string
@@ -220,6 +397,77 @@ type PSMDBParams struct {
 	Expose            bool
 	Replicaset        *Replicaset
 	PMM               *PMM
+	// AllowUnsafe allows creating cluster of size which is prone to split-brain.
+	AllowUnsafe bool
+	// SkipCapacityCheck disables checking that requested pod resources fit on a node.
+	SkipCapacityCheck bool
+	// NodeSelector restricts cluster pods to nodes with matching labels, capacity check uses the same nodes.
+	NodeSelector map[string]string
+	// SchedulerName is a name of custom scheduler for cluster pods, default scheduler is used if empty.
+	SchedulerName string
+	// MongoPort is a port mongod and mongos listen on, 27017 is used if empty.
+	MongoPort int32
+	// Encryption configures data-at-rest encryption, it's enabled with AES256-CBC if empty.
+	Encryption *EncryptionParams
+	// BackupResources are resource limits of backup agent container, operator defaults are used if empty.
+	BackupResources *ComputeResources
+	// BackupServiceAccount is a service account of backup agent, e.g. one bound to cloud IAM role for S3 access.
+	// Operator's service account is used if empty.
+	BackupServiceAccount string
+	// TemplateName is a name of template registered with RegisterTemplate to create cluster from.
+	// Template from CR templates directory is used if empty.
+	TemplateName string
+	// ProfilingMode is one of all, slowOp or off, slowOp is used if empty.
+	ProfilingMode string
+	// SlowOpThresholdMs is a threshold of slow operations in milliseconds, 100 is used if zero.
+	SlowOpThresholdMs int
+	// RateLimit is a rate of profiled operations, 100 is used if zero.
+	RateLimit int
+	// EncryptionKeySecret is a name of user-managed secret with encryption key.
+	// If empty, the key is generated by the operator and deleted together with the cluster.
+	EncryptionKeySecret string
+	// ExposeInternal makes exposed cluster reachable from the cluster's VPC only, it's supported for EKS, GKE and AKS.
+	ExposeInternal bool
+	// ReplsetName is a name of data replica set, rs0 is used if empty.
+	ReplsetName string
+	// ConfigServerSize is a number of config server replicas.
+	// If zero, 3 is used, or 1 for clusters of less than 3 nodes.
+	ConfigServerSize int32
+	// SecurityContext is set on mongod pods of replica set and config servers, operator defaults are used if nil.
+	SecurityContext *SecurityContext
+	// PriorityClassName is a name of existing priority class of mongod and mongos pods.
+	PriorityClassName string
+	// ClusterDomain is a DNS domain of Kubernetes cluster used in internal hostnames, cluster.local is used if empty.
+	ClusterDomain string
+	// BackupCompression is a compression type of backup tasks without one, operator default is used if empty.
+	// Tasks come from template or overrides, cluster without them can't be created with BackupCompression.
+	BackupCompression string
+	// ExternalMembers are replica set members running outside of the cluster, e.g. in another data center.
+	// Members managed by the operator always have one vote and default priority.
+	ExternalMembers []ReplsetMember
+	// MongosServiceAnnotations are set on exposed mongos service, or on replica set service for single node clusters.
+	MongosServiceAnnotations map[string]string
+	// Overrides are deep-merged into generated custom resource before applying it.
+	Overrides map[string]interface{} `yaml:",omitempty"`
+}
+
+// SecurityContext contains user and groups database pods run as, e.g. ones allowed in restricted namespace.
+// Unset fields are left to operator defaults.
+type SecurityContext struct {
+	RunAsUser  *int64
+	RunAsGroup *int64
+	FSGroup    *int64
+}
+
+// ReplsetMember contains address and election settings of external PSMDB replica set member.
+type ReplsetMember struct {
+	Host string
+	// Port is a port of mongod, 27017 is used if empty.
+	Port int
+	// Priority is in [0, 1000] range, members with zero priority never become primary.
+	Priority int
+	// Votes is either 0 or 1, non-voting members must have zero priority.
+	Votes int
 }
 
 type appStatus struct {
@@ -232,7 +480,9 @@ type DetailedState []appStatus
 
 // PXCCluster contains information related to pxc cluster.
 type PXCCluster struct {
-	Name          string
+	Name string
+	// UID identifies the cluster custom resource, it differs for clusters recreated with the same name.
+	UID           string
 	Message       string
 	Size          int32
 	Pause         bool
@@ -242,11 +492,15 @@ type PXCCluster struct {
 	PXC           *PXC
 	ProxySQL      *ProxySQL
 	HAProxy       *HAProxy
+	// CreatedAt is a creation time of cluster custom resource.
+	CreatedAt time.Time
 }
 
 // PSMDBCluster contains information related to psmdb cluster.
 type PSMDBCluster struct {
-	Name          string
+	Name string
+	// UID identifies the cluster custom resource, it differs for clusters recreated with the same name.
+	UID           string
 	Image         string
 	Message       string
 	Size          int32
@@ -255,6 +509,8 @@ type PSMDBCluster struct {
 	State         ClusterState
 	DetailedState DetailedState
 	Replicaset    *Replicaset
+	// CreatedAt is a creation time of cluster custom resource.
+	CreatedAt time.Time
 }
 
 // PSMDBCredentials represents PSMDB connection credentials.
@@ -274,6 +530,30 @@ type PXCCredentials struct {
 	Port     int32
 }
 
+// ConnectionURI returns MongoDB connection string with URL-escaped credentials.
+func (c *PSMDBCredentials) ConnectionURI() string {
+	u := url.URL{
+		Scheme: "mongodb",
+		User:   url.UserPassword(c.Username, c.Password),
+		Host:   net.JoinHostPort(c.Host, strconv.Itoa(int(c.Port))),
+		Path:   "/",
+	}
+	if c.Replicaset != "" {
+		u.RawQuery = url.Values{"replicaSet": []string{c.Replicaset}}.Encode()
+	}
+	return u.String()
+}
+
+// ConnectionURI returns MySQL connection string with URL-escaped credentials.
+func (c *PXCCredentials) ConnectionURI() string {
+	u := url.URL{
+		Scheme: "mysql",
+		User:   url.UserPassword(c.Username, c.Password),
+		Host:   net.JoinHostPort(c.Host, strconv.Itoa(int(c.Port))),
+	}
+	return u.String()
+}
+
 type extraCRParams struct {
 	secretName  string
 	secrets     map[string][]byte
@@ -301,22 +581,86 @@ var (
 	// ErrNotFound should be returned when referenced resource does not exist
 	// inside Kubernetes cluster.
 	ErrNotFound error = errors.New("resource was not found in Kubernetes cluster")
+	// ErrUnsafeClusterSize should be returned when requested cluster size is prone to split-brain.
+	ErrUnsafeClusterSize = errors.New("cluster size is unsafe, use 1 or 3 and more nodes")
+	// ErrClustersExist should be returned when operator CRDs can't be deleted because there are clusters,
+	// backups or restores managed by them.
+	ErrClustersExist = errors.New("there are database clusters managed by the operator")
+	// ErrAPIVersionNotInstalled should be returned when custom resource API version is not served by installed operator.
+	ErrAPIVersionNotInstalled = errors.New("custom resource API version is not installed")
+	// ErrBackupInProgress should be returned when cluster can't be deleted because of unfinished backup.
+	ErrBackupInProgress = errors.New("backup is in progress")
+	// ErrTemplateNotFound should be returned when named custom resource template is not registered.
+	ErrTemplateNotFound = errors.New("custom resource template is not registered")
+	// ErrInvalidProfilingMode should be returned when unknown MongoDB operation profiling mode is requested.
+	ErrInvalidProfilingMode = errors.New("invalid operation profiling mode")
+	// ErrInvalidProfilingParams should be returned when MongoDB operation profiling parameters are out of range.
+	ErrInvalidProfilingParams = errors.New("invalid operation profiling parameters")
+	// ErrInvalidCipherMode should be returned when unknown encryption cipher mode is requested.
+	ErrInvalidCipherMode = errors.New("invalid encryption cipher mode")
+	// ErrInternalExposeNotSupported should be returned when internal load balancer is requested for unsupported Kubernetes cluster type.
+	ErrInternalExposeNotSupported = errors.New("internal load balancer is not supported for this Kubernetes cluster type")
+	// ErrS3StorageCheckFailed should be returned when S3 storage is not accessible with given credentials.
+	ErrS3StorageCheckFailed = errors.New("S3 storage check failed")
+	// ErrInvalidProxyConfig should be returned when PXC cluster proxies are misconfigured.
+	ErrInvalidProxyConfig = errors.New("invalid proxy configuration")
+	// ErrInvalidReplsetMembers should be returned when replica set members priorities or votes are invalid.
+	ErrInvalidReplsetMembers = errors.New("invalid replica set members")
+	// ErrInvalidSecurityContext should be returned when pod security context has IDs out of allowed range.
+	ErrInvalidSecurityContext = errors.New("invalid security context")
+	// ErrInvalidTerminationGracePeriod should be returned when termination grace period is negative.
+	ErrInvalidTerminationGracePeriod = errors.New("invalid termination grace period")
+	// ErrInvalidClusterDomain should be returned when cluster domain isn't a valid DNS subdomain.
+	ErrInvalidClusterDomain = errors.New("invalid cluster domain")
+	// ErrInvalidBackupCompression should be returned when unsupported backup compression type is requested.
+	ErrInvalidBackupCompression = errors.New("invalid backup compression")
+	// ErrClusterPasswordsLost should be returned when cluster secret can't be recreated because cluster
+	// has been ready and operator's internal secret with passwords in use is missing too.
+	ErrClusterPasswordsLost = errors.New("cluster passwords are lost")
+	// ErrInvalidConfigServerSize should be returned when negative number of config server replicas is requested.
+	ErrInvalidConfigServerSize = errors.New("invalid config server size")
+	// ErrInvalidLogTailLines should be returned when negative number of log lines is requested.
+	ErrInvalidLogTailLines = errors.New("number of log lines must not be negative")
+	// ErrInvalidReplsetName should be returned when replica set name can't be used in names of Kubernetes objects.
+	ErrInvalidReplsetName = errors.New("invalid replica set name")
+	// ErrInvalidComputeResources should be returned when CPU or memory of a container can't be parsed.
+	ErrInvalidComputeResources = errors.New("invalid compute resources")
+	// ErrInvalidBackupStorage should be returned when backup storage or schedule is misconfigured.
+	ErrInvalidBackupStorage = errors.New("invalid backup storage")
+	// ErrResourcesExceedNodeCapacity should be returned when requested pod resources don't fit on any worker node.
+	ErrResourcesExceedNodeCapacity = errors.New("requested resources exceed capacity of the largest node")
+	// ErrPMMAPIKeyNotSupported should be returned when PMM API key is requested for operator which can't use it.
+	ErrPMMAPIKeyNotSupported = errors.New("PMM API key is not supported by operator version")
+	// ErrOperatorUpgrading should be returned when cluster can't be created because operator rollout is in progress.
+	ErrOperatorUpgrading = errors.New("operator is being upgraded, retry later")
 	// ErrEmptyResponse is a sentinel error to state it is not possible to get the CR version
 	// since the response was empty.
 	ErrEmptyResponse = errors.New("cannot get the CR version. Empty response")
 	// v112 used to select the correct structure for different operator versions.
 	v112, _ = goversion.NewVersion("1.12") //nolint:gochecknoglobals
+	// v113 is the first PSMDB operator version reading PMM API key from cluster secret.
+	v113, _ = goversion.NewVersion("1.13") //nolint:gochecknoglobals
 )
 
 var pmmClientImage string //nolint:gochecknoglobals
 
 // K8sClient is a client for Kubernetes.
 type K8sClient struct {
-	kubeCtl    *kubectl.KubeCtl
-	kube       *kube.Client
-	l          logger.Logger
-	kubeconfig string
-	client     *http.Client
+	kubeCtl *kubectl.KubeCtl
+	kube    *kube.Client
+	l       logger.Logger
+	client  *http.Client
+	// manifestClient is used to download operator manifests which can be several megabytes large.
+	manifestClient *http.Client
+	// crTemplatesDir is a directory with custom resource templates.
+	crTemplatesDir string
+	// impersonateUser and impersonateGroups are set by WithImpersonation.
+	impersonateUser   string
+	impersonateGroups []string
+	// kubeConnectionPool is set by WithKubeConnectionPool.
+	kubeConnectionPool *ConnectionPool
+	// s3CheckImage is set by WithS3CheckImage.
+	s3CheckImage string
 }
 
 func init() {
@@ -365,62 +709,340 @@ func (d DetailedState) CountAllPods() (count int32) {
 	return
 }
 
+// ReadyFraction returns fraction of ready pods of the database cluster in range from 0 to 1.
+// It returns 0 if there are no pods.
+func (d DetailedState) ReadyFraction() float64 {
+	all := d.CountAllPods()
+	if all == 0 {
+		return 0
+	}
+	return float64(d.CountReadyPods()) / float64(all)
+}
+
+// IsFullyReady returns true if all pods of the database cluster are ready.
+// Cluster without pods is not considered ready.
+func (d DetailedState) IsFullyReady() bool {
+	all := d.CountAllPods()
+	return all > 0 && d.CountReadyPods() >= all
+}
+
+// Option configures K8sClient.
+type Option func(*K8sClient)
+
+// WithHTTPTimeout sets timeout of HTTP requests expected to be quick.
+func WithHTTPTimeout(timeout time.Duration) Option {
+	return func(c *K8sClient) {
+		c.client.Timeout = timeout
+	}
+}
+
+// WithManifestFetchTimeout sets timeout of operator manifests downloads.
+func WithManifestFetchTimeout(timeout time.Duration) Option {
+	return func(c *K8sClient) {
+		c.manifestClient.Timeout = timeout
+	}
+}
+
+// WithCABundle makes HTTP clients trust certificates from given PEM bundle in addition to system ones.
+// It's required when manifests are fetched through a TLS-intercepting proxy.
+func WithCABundle(pemCerts []byte) Option {
+	return func(c *K8sClient) {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			c.l.Warnf("cannot load system certificates, only CA bundle will be trusted: %v", err)
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pemCerts) {
+			c.l.Warn("CA bundle doesn't contain any valid certificate, ignoring it")
+			return
+		}
+		for _, client := range []*http.Client{c.client, c.manifestClient} {
+			transport, ok := client.Transport.(*http.Transport)
+			if !ok {
+				continue
+			}
+			tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+			if transport.TLSClientConfig != nil {
+				tlsConfig = transport.TLSClientConfig.Clone()
+			}
+			tlsConfig.RootCAs = pool
+			transport.TLSClientConfig = tlsConfig
+		}
+	}
+}
+
+// WithImpersonation makes Kubernetes API requests on behalf of given user and groups
+// instead of the identity from kubeconfig, so the real actor is visible in audit logs.
+// Kubeconfig identity must be allowed to impersonate them.
+func WithImpersonation(user string, groups ...string) Option {
+	return func(c *K8sClient) {
+		c.impersonateUser = user
+		c.impersonateGroups = groups
+	}
+}
+
+// ConnectionPool configures idle HTTP connections kept for reuse, zero fields keep defaults.
+type ConnectionPool = kube.ConnectionPool
+
+// WithKubeConnectionPool configures idle connections pool of Kubernetes API client.
+// API servers usually speak HTTP/2 and multiplex all requests over a single connection,
+// then only IdleConnTimeout matters: raise it from default 90 seconds if requests come
+// in bursts less often than that, so the connection and its TLS session are reused.
+// Over HTTP/1.1 up to 25 idle connections to API server are kept by default and requests above
+// that open new connections with TLS handshake each, so raise MaxIdleConnsPerHost if many
+// clusters are managed concurrently.
+func WithKubeConnectionPool(pool ConnectionPool) Option {
+	return func(c *K8sClient) {
+		c.kubeConnectionPool = &pool
+	}
+}
+
+// WithHTTPConnectionPool configures idle connections pool of HTTP clients used to fetch
+// operator manifests and list operator versions. By default up to 10 idle connections are kept for 90 seconds.
+// If only MaxIdleConnsPerHost is set, MaxIdleConns is raised to it when needed as the total limit applies first.
+func WithHTTPConnectionPool(pool ConnectionPool) Option {
+	return func(c *K8sClient) {
+		for _, client := range []*http.Client{c.client, c.manifestClient} {
+			transport, ok := client.Transport.(*http.Transport)
+			if !ok {
+				continue
+			}
+			if pool.MaxIdleConns > 0 {
+				transport.MaxIdleConns = pool.MaxIdleConns
+			}
+			if pool.MaxIdleConnsPerHost > 0 {
+				transport.MaxIdleConnsPerHost = pool.MaxIdleConnsPerHost
+				if pool.MaxIdleConns == 0 && transport.MaxIdleConns > 0 && transport.MaxIdleConns < pool.MaxIdleConnsPerHost {
+					transport.MaxIdleConns = pool.MaxIdleConnsPerHost
+				}
+			}
+			if pool.IdleConnTimeout > 0 {
+				transport.IdleConnTimeout = pool.IdleConnTimeout
+			}
+		}
+	}
+}
+
+// WithS3CheckImage sets image with AWS CLI used by ValidateS3Storage, e.g. a mirror in private registry
+// for clusters without access to Docker Hub. amazon/aws-cli is used by default.
+func WithS3CheckImage(image string) Option {
+	return func(c *K8sClient) {
+		c.s3CheckImage = image
+	}
+}
+
+// kubeOptions returns options of Kubernetes API client set by K8sClient options.
+func (c *K8sClient) kubeOptions() []kube.Option {
+	var opts []kube.Option
+	if c.impersonateUser != "" || len(c.impersonateGroups) != 0 {
+		opts = append(opts, kube.WithImpersonation(c.impersonateUser, c.impersonateGroups))
+	}
+	if c.kubeConnectionPool != nil {
+		opts = append(opts, kube.WithConnectionPool(*c.kubeConnectionPool))
+	}
+	return opts
+}
+
+// newHTTPClient returns HTTP client which honors HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables.
+func newHTTPClient(timeout time.Duration) *http.Client {
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			Proxy:               http.ProxyFromEnvironment,
+			MaxIdleConns:        defaultHTTPMaxIdleConns,
+			MaxIdleConnsPerHost: defaultHTTPMaxIdleConns,
+			IdleConnTimeout:     defaultHTTPIdleConnTimeout,
+		},
+	}
+}
+
+// applyOptions applies CA bundle set by DBAAS_CA_BUNDLE_FILE environment variable, if any, and given options.
+func (c *K8sClient) applyOptions(opts []Option) *K8sClient {
+	if file := os.Getenv(caBundleFileEnv); file != "" {
+		pemCerts, err := ioutil.ReadFile(file) //nolint:gosec
+		if err != nil {
+			c.l.Warnf("cannot read CA bundle %q: %v", file, err)
+		} else {
+			WithCABundle(pemCerts)(c)
+		}
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
 // New returns new K8Client object.
-func New(ctx context.Context, kubeconfig string) (*K8sClient, error) {
+func New(ctx context.Context, kubeconfig string, opts ...Option) (*K8sClient, error) {
 	l := logger.Get(ctx)
 	l = l.WithField("component", "K8sClient")
 
+	c := &K8sClient{
+		l:              l,
+		client:         newHTTPClient(defaultHTTPTimeout),
+		manifestClient: newHTTPClient(defaultManifestFetchTimeout),
+		crTemplatesDir: crTemplatesDir(),
+	}
+	c.applyOptions(opts)
+
 	kubeCtl, err := kubectl.NewKubeCtl(ctx, kubeconfig)
 	if err != nil {
 		return nil, err
 	}
+	if c.impersonateUser != "" || len(c.impersonateGroups) != 0 {
+		kubeCtl.Impersonate(c.impersonateUser, c.impersonateGroups)
+	}
+	c.kubeCtl = kubeCtl
 
-	kube, err := kube.NewFromKubeConfigString(kubeconfig)
+	c.kube, err = kube.NewFromKubeConfigString(kubeconfig, c.kubeOptions()...)
 	if err != nil {
 		return nil, err
 	}
-	return &K8sClient{
-		kube:    kube,
-		kubeCtl: kubeCtl,
-		l:       l,
-		client: &http.Client{
-			Timeout: time.Second * 5,
-			Transport: &http.Transport{
-				MaxIdleConns:    1,
-				IdleConnTimeout: 10 * time.Second,
-			},
-		},
-		kubeconfig: kubeconfig,
-	}, nil
+	return c, nil
 }
 
 // NewIncluster returns new K8Client object.
-func NewIncluster(ctx context.Context) (*K8sClient, error) {
+func NewIncluster(ctx context.Context, opts ...Option) (*K8sClient, error) {
 	l := logger.Get(ctx)
 	l = l.WithField("component", "K8sClient")
 
-	kube, err := kube.NewFromIncluster()
+	c := &K8sClient{
+		l:              l,
+		client:         newHTTPClient(defaultHTTPTimeout),
+		manifestClient: newHTTPClient(defaultManifestFetchTimeout),
+		crTemplatesDir: crTemplatesDir(),
+	}
+	c.applyOptions(opts)
+
+	var err error
+	c.kube, err = kube.NewFromIncluster(c.kubeOptions()...)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// crTemplatesDir returns directory with custom resource templates
+// set by DBAAS_CR_TEMPLATES_DIR environment variable or default one.
+func crTemplatesDir() string {
+	if dir := os.Getenv(crTemplatesDirEnv); dir != "" {
+		return dir
+	}
+	return defaultCRTemplatesDir
+}
+
+// readCRTemplate reads custom resource template file from templates directory.
+func (c *K8sClient) readCRTemplate(file string) ([]byte, error) {
+	dir := c.crTemplatesDir
+	if dir == "" {
+		dir = defaultCRTemplatesDir
+	}
+	path := filepath.Join(dir, file)
+	bytes, err := ioutil.ReadFile(path) //nolint:gosec
 	if err != nil {
+		c.l.Debugf("cannot read CR template %q, falling back to defaults: %v", path, err)
 		return nil, err
 	}
-	return &K8sClient{
-		kube: kube,
-		l:    l,
-		client: &http.Client{
-			Timeout: time.Second * 5,
-			Transport: &http.Transport{
-				MaxIdleConns:    1,
-				IdleConnTimeout: 10 * time.Second,
+	c.l.Infof("using CR template %q", path)
+	return bytes, nil
+}
+
+// RegisterTemplate stores custom resource template of PXC or PSMDB cluster under given name,
+// so clusters can be created from it by setting TemplateName param. Existing template with the same name is replaced.
+// Templates are kept in dbaas-cr-templates config map.
+func (c *K8sClient) RegisterTemplate(ctx context.Context, name string, manifest []byte) error {
+	ctx = c.withRequestLogger(ctx, "RegisterTemplate", name)
+	if errs := validation.IsConfigMapKey(name); len(errs) != 0 {
+		return errors.Errorf("invalid template name %q: %s", name, strings.Join(errs, ", "))
+	}
+	var typeMeta metav1.TypeMeta
+	if err := c.unmarshalTemplate(manifest, &typeMeta); err != nil {
+		return errors.Wrap(err, "cannot parse template")
+	}
+	if typeMeta.Kind != kube.PXCKind && typeMeta.Kind != kube.PSMDBKind {
+		return errors.Errorf("unsupported template kind %q, expected %s or %s", typeMeta.Kind, kube.PXCKind, kube.PSMDBKind)
+	}
+
+	configMap, err := c.kube.GetConfigMap(ctx, crTemplatesConfigMap)
+	if err != nil {
+		if !apiErrors.IsNotFound(err) {
+			return errors.Wrap(err, "cannot get templates")
+		}
+		configMap = &corev1.ConfigMap{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: k8sAPIVersion,
+				Kind:       k8sMetaKindConfigMap,
 			},
-		},
-	}, nil
+			ObjectMeta: metav1.ObjectMeta{
+				Name: crTemplatesConfigMap,
+			},
+		}
+	}
+	if configMap.Data == nil {
+		configMap.Data = make(map[string]string, 1)
+	}
+	configMap.Data[name] = string(manifest)
+	return c.kube.Apply(ctx, configMap)
+}
+
+// getNamedTemplate returns template registered with RegisterTemplate, it must be of given kind.
+func (c *K8sClient) getNamedTemplate(ctx context.Context, name, kind string) ([]byte, error) {
+	configMap, err := c.kube.GetConfigMap(ctx, crTemplatesConfigMap)
+	if err != nil && !apiErrors.IsNotFound(err) {
+		return nil, errors.Wrap(err, "cannot get templates")
+	}
+	if configMap == nil || configMap.Data[name] == "" {
+		return nil, errors.Wrapf(ErrTemplateNotFound, "%q", name)
+	}
+	manifest := []byte(configMap.Data[name])
+	var typeMeta metav1.TypeMeta
+	if err := c.unmarshalTemplate(manifest, &typeMeta); err != nil {
+		return nil, errors.Wrapf(err, "cannot parse template %q", name)
+	}
+	if typeMeta.Kind != kind {
+		return nil, errors.Errorf("template %q is for %s, not %s", name, typeMeta.Kind, kind)
+	}
+	return manifest, nil
 }
 
 // Cleanup removes temporary files created by that object.
 func (c *K8sClient) Cleanup() error {
+	// In-cluster client does not use kubectl.
+	if c.kubeCtl == nil {
+		return nil
+	}
 	return c.kubeCtl.Cleanup()
 }
 
+// requestLoggerKey is a context key for request-scoped K8sClient logger.
+type requestLoggerKey struct{}
+
+// withRequestLogger returns derived context with a child of the client logger
+// scoped to given operation on given cluster; use requestLogger to get it.
+// Request id is inherited from the client logger as services create clients from gRPC request context;
+// a new one is generated for calls without a logger in context.
+func (c *K8sClient) withRequestLogger(ctx context.Context, operation, cluster string) context.Context {
+	l := c.l
+	if _, ok := logger.FromContext(ctx); !ok {
+		l = l.WithField("request", uuid.New().String())
+	}
+	l = l.WithField("operation", operation).WithField("cluster", cluster)
+	return context.WithValue(ctx, requestLoggerKey{}, l)
+}
+
+// requestLogger returns logger set by withRequestLogger, or the client logger if there is none.
+func (c *K8sClient) requestLogger(ctx context.Context) logger.Logger {
+	if l, ok := ctx.Value(requestLoggerKey{}).(logger.Logger); ok {
+		return l
+	}
+	return c.l
+}
+
+// KubectlError is returned by Run and other kubectl based methods when kubectl command fails.
+// It contains kubectl exit code, standard error output and arguments.
+type KubectlError = kubectl.KubectlError
+
+// Run runs kubectl with given arguments. Failures are returned as *KubectlError.
 func (c *K8sClient) Run(ctx context.Context, params []string) ([]byte, error) {
 	return c.kubeCtl.Run(ctx, params, nil)
 }
@@ -439,14 +1061,15 @@ func (c *K8sClient) Delete(ctx context.Context, res interface{}) error {
 
 // GetKubeconfig generates kubeconfig compatible with kubectl for incluster created clients.
 func (c *K8sClient) GetKubeconfig(ctx context.Context) (string, error) {
+	ctx = c.withRequestLogger(ctx, "GetKubeconfig", "")
 	secret, err := c.kube.GetSecretsForServiceAccount(ctx, "pmm-service-account")
 	if err != nil {
-		c.l.Errorf("failed getting service account: %v", err)
+		c.requestLogger(ctx).Errorf("failed getting service account: %v", err)
 		return "", err
 	}
 	kubeConfig, err := c.kube.GenerateKubeConfig(secret)
 	if err != nil {
-		c.l.Errorf("failed generating kubeconfig: %v", err)
+		c.requestLogger(ctx).Errorf("failed generating kubeconfig: %v", err)
 		return "", err
 	}
 	return string(kubeConfig), nil
@@ -454,7 +1077,17 @@ func (c *K8sClient) GetKubeconfig(ctx context.Context) (string, error) {
 
 // ListPXCClusters returns list of Percona XtraDB clusters and their statuses.
 func (c *K8sClient) ListPXCClusters(ctx context.Context) ([]PXCCluster, error) {
-	perconaXtraDBClusters, err := c.getPerconaXtraDBClusters(ctx)
+	return c.listPXCClusters(c.withRequestLogger(ctx, "ListPXCClusters", ""), false)
+}
+
+// ListManagedPXCClusters returns list of Percona XtraDB clusters created by dbaas-controller and their statuses.
+// Clusters being deleted are always included: their custom resources, and so the label, are already gone.
+func (c *K8sClient) ListManagedPXCClusters(ctx context.Context) ([]PXCCluster, error) {
+	return c.listPXCClusters(c.withRequestLogger(ctx, "ListManagedPXCClusters", ""), true)
+}
+
+func (c *K8sClient) listPXCClusters(ctx context.Context, managedOnly bool) ([]PXCCluster, error) {
+	perconaXtraDBClusters, err := c.getPerconaXtraDBClusters(ctx, managedOnly)
 	if err != nil {
 		return nil, err
 	}
@@ -470,6 +1103,7 @@ func (c *K8sClient) ListPXCClusters(ctx context.Context) ([]PXCCluster, error) {
 
 // CreateSecret creates secret resource to use as credential source for clusters.
 func (c *K8sClient) CreateSecret(ctx context.Context, secretName string, data map[string][]byte) error {
+	ctx = c.withRequestLogger(ctx, "CreateSecret", "")
 	secret := &corev1.Secret{ //nolint: exhaustruct
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: k8sAPIVersion,
@@ -486,14 +1120,63 @@ func (c *K8sClient) CreateSecret(ctx context.Context, secretName string, data ma
 
 // CreatePXCCluster creates Percona XtraDB cluster with provided parameters.
 func (c *K8sClient) CreatePXCCluster(ctx context.Context, params *PXCParams) error {
+	ctx = c.withRequestLogger(ctx, "CreatePXCCluster", params.Name)
+	l := c.requestLogger(ctx)
+	l.Debug("creating cluster")
+
 	if (params.ProxySQL != nil) == (params.HAProxy != nil) {
-		return errors.New("pxc cluster must have one and only one proxy type defined")
+		return errors.Wrap(ErrInvalidProxyConfig, "pxc cluster must have one and only one proxy type defined")
+	}
+
+	err := validateClusterSize(params.Size, params.AllowUnsafe)
+	if err != nil {
+		return err
+	}
+	err = validatePMMParams(params.PMM)
+	if err != nil {
+		return err
+	}
+	err = validateComputeResources(params.BackupResources, "backup")
+	if err != nil {
+		return err
+	}
+	err = validateSecurityContext(params.SecurityContext)
+	if err != nil {
+		return err
+	}
+	err = validateTerminationGracePeriod(params.TerminationGracePeriodSeconds)
+	if err != nil {
+		return err
+	}
+	if !params.SkipCapacityCheck {
+		resources := []*ComputeResources{}
+		if params.PXC != nil {
+			resources = append(resources, params.PXC.ComputeResources)
+		}
+		if params.ProxySQL != nil {
+			resources = append(resources, params.ProxySQL.ComputeResources)
+		}
+		if params.HAProxy != nil {
+			resources = append(resources, params.HAProxy.ComputeResources)
+		}
+		if err = c.validateNodeCapacity(ctx, params.NodeSelector, resources...); err != nil {
+			return err
+		}
 	}
 
-	_, err := c.kube.GetPXCCluster(ctx, params.Name)
+	_, err = c.kube.GetPXCCluster(ctx, params.Name)
 	if err == nil {
 		return fmt.Errorf(clusterWithSameNameExistsErrTemplate, params.Name)
 	}
+	if err = c.checkOperatorNotUpgrading(ctx, pxcOperatorDeploymentName); err != nil {
+		return err
+	}
+	if err = c.validateServiceAccount(ctx, params.BackupServiceAccount); err != nil {
+		return err
+	}
+	if err = c.validatePriorityClass(ctx, params.PriorityClassName); err != nil {
+		return err
+	}
 
 	secretName := fmt.Sprintf(pxcSecretNameTmpl, params.Name)
 	secrets, err := generatePXCPasswords()
@@ -508,20 +1191,40 @@ func (c *K8sClient) CreatePXCCluster(ctx context.Context, params *PXCParams) err
 		return err
 	}
 	if params.PMM != nil {
-		secrets["pmmserver"] = []byte(params.PMM.Password)
+		pmmSecrets, err := pxcPMMSecrets(operators.PXCOperatorVersion, params.PMM)
+		if err != nil {
+			return err
+		}
+		for k, v := range pmmSecrets {
+			secrets[k] = v
+		}
 	}
 
 	var serviceType corev1.ServiceType
+	var serviceAnnotations map[string]string
 	// This enables ingress for the cluster and exposes the cluster to the world.
 	// The cluster will have an internal IP and a world accessible hostname.
 	// This feature cannot be tested with minikube. Please use EKS for testing.
 	if clusterType := c.GetKubernetesClusterType(ctx); clusterType != MinikubeClusterType && params.Expose {
 		serviceType = corev1.ServiceTypeLoadBalancer
+		if params.ExposeInternal {
+			if serviceAnnotations, err = internalExposeAnnotations(clusterType); err != nil {
+				return err
+			}
+		}
 	} else {
 		serviceType = corev1.ServiceTypeNodePort
 	}
 
-	spec, err := c.createPXCSpecFromParams(params, &secretName, operators.PXCOperatorVersion, storageName, serviceType)
+	spec, err := c.createPXCSpecFromParams(ctx, params, &secretName, operators.PXCOperatorVersion, storageName, serviceType)
+	if err != nil {
+		return err
+	}
+	if err = c.recordOperation(&spec.ObjectMeta, OperationCreate); err != nil {
+		return err
+	}
+	setPXCServiceAnnotations(spec, serviceAnnotations)
+	err = validateCRAPIVersion(&spec.TypeMeta, c.getAPIVersionForPXCOperator(operators.PXCOperatorVersion), operators.apiVersions, pxcAPINamespace)
 	if err != nil {
 		return err
 	}
@@ -531,13 +1234,131 @@ func (c *K8sClient) CreatePXCCluster(ctx context.Context, params *PXCParams) err
 		return errors.Wrap(err, "cannot create secret for PXC")
 	}
 
-	return c.kube.Apply(ctx, spec)
+	return c.applyWithRetry(ctx, spec)
+}
+
+// validateClusterSize checks that cluster of given size is able to elect primary.
+// Sane sizes are 1 (no high availability, unsafe config is enabled for it) and 3 or more.
+// Two nodes can't form a majority after losing one of them, so such clusters are rejected
+// unless allowUnsafe is set.
+func validateClusterSize(size int32, allowUnsafe bool) error {
+	if size == 2 && !allowUnsafe {
+		return errors.Wrapf(ErrUnsafeClusterSize, "cluster size %d", size)
+	}
+	return nil
+}
+
+// validateNodeCapacity checks that pods with given resources can be scheduled on at least one worker node
+// matching nodeSelector. The check is skipped if user is not allowed to list nodes.
+func (c *K8sClient) validateNodeCapacity(ctx context.Context, nodeSelector map[string]string, resources ...*ComputeResources) error {
+	nodes, err := c.getWorkerNodes(ctx, nodeSelector)
+	if err != nil {
+		if apiErrors.IsForbidden(errors.Cause(err)) {
+			c.requestLogger(ctx).Warnf("skipping node capacity check: %v", err)
+			return nil
+		}
+		return err
+	}
+	return checkNodeCapacity(nodes, resources...)
+}
+
+// psmdbPodResources returns resources requested by mongod, arbiter, config server and mongos pods of the cluster.
+func psmdbPodResources(spec *psmdbv1.PerconaServerMongoDB) []*ComputeResources {
+	var res []*ComputeResources
+	for _, rs := range spec.Spec.Replsets {
+		res = append(res, requestedResources(rs.Resources))
+		if rs.Arbiter.Enabled {
+			res = append(res, requestedResources(rs.Arbiter.Resources))
+		}
+	}
+	if spec.Spec.Sharding.Enabled {
+		if cfg := spec.Spec.Sharding.ConfigsvrReplSet; cfg != nil {
+			res = append(res, requestedResources(cfg.Resources))
+		}
+		if mongos := spec.Spec.Sharding.Mongos; mongos != nil {
+			res = append(res, requestedResources(mongos.Resources))
+		}
+	}
+	return res
+}
+
+// requestedResources returns CPU and memory reserved for the container by the scheduler:
+// requests, or limits if requests are not set.
+func requestedResources(req corev1.ResourceRequirements) *ComputeResources {
+	quantity := func(name corev1.ResourceName) string {
+		if q, ok := req.Requests[name]; ok {
+			return q.String()
+		}
+		if q, ok := req.Limits[name]; ok {
+			return q.String()
+		}
+		return ""
+	}
+	return &ComputeResources{CPUM: quantity(corev1.ResourceCPU), MemoryBytes: quantity(corev1.ResourceMemory)}
+}
+
+// checkNodeCapacity returns ErrResourcesExceedNodeCapacity if any of given pod resources
+// don't fit into allocatable resources of any node. Empty list of nodes is not checked.
+func checkNodeCapacity(nodes []corev1.Node, resources ...*ComputeResources) error {
+	if len(nodes) == 0 {
+		return nil
+	}
+	allocatable := make([][2]uint64, 0, len(nodes))
+	var maxCPUMillis, maxMemoryBytes uint64
+	for _, node := range nodes {
+		cpuMillis, memoryBytes, err := getResources(node.Status.Allocatable)
+		if err != nil {
+			return errors.Wrap(err, "could not get allocatable resources of the node")
+		}
+		allocatable = append(allocatable, [2]uint64{cpuMillis, memoryBytes})
+		if cpuMillis > maxCPUMillis {
+			maxCPUMillis = cpuMillis
+		}
+		if memoryBytes > maxMemoryBytes {
+			maxMemoryBytes = memoryBytes
+		}
+	}
+
+	for _, res := range resources {
+		if res == nil {
+			continue
+		}
+		var cpuMillis, memoryBytes uint64
+		var err error
+		if res.CPUM != "" {
+			if cpuMillis, err = convertors.StrToMilliCPU(res.CPUM); err != nil {
+				return errors.Wrapf(err, "failed to convert '%s' to millicpus", res.CPUM)
+			}
+		}
+		if res.MemoryBytes != "" {
+			if memoryBytes, err = convertors.StrToBytes(res.MemoryBytes); err != nil {
+				return errors.Wrapf(err, "failed to convert '%s' to bytes", res.MemoryBytes)
+			}
+		}
+		fits := false
+		for _, a := range allocatable {
+			if cpuMillis <= a[0] && memoryBytes <= a[1] {
+				fits = true
+				break
+			}
+		}
+		if !fits {
+			return errors.Wrapf(ErrResourcesExceedNodeCapacity,
+				"requested %dm CPU and %d bytes of memory per pod, largest node has %dm CPU and %d bytes of memory allocatable",
+				cpuMillis, memoryBytes, maxCPUMillis, maxMemoryBytes)
+		}
+	}
+	return nil
 }
 
 // UpdatePXCCluster changes size of provided Percona XtraDB cluster.
 func (c *K8sClient) UpdatePXCCluster(ctx context.Context, params *PXCParams) error {
+	ctx = c.withRequestLogger(ctx, "UpdatePXCCluster", params.Name)
+	l := c.requestLogger(ctx)
+	l.Debug("updating cluster")
+
 	if (params.ProxySQL != nil) && (params.HAProxy != nil) {
-		return errors.New("can't update both proxies, only one should be in use")
+		return errors.Wrap(ErrInvalidProxyConfig, "can't update both proxies, only one should be in use")
 	}
 
 	cluster, err := c.kube.GetPXCCluster(ctx, params.Name)
@@ -552,8 +1373,11 @@ func (c *K8sClient) UpdatePXCCluster(ctx context.Context, params *PXCParams) err
 	clusterState := c.getClusterState(ctx, clusterInfo, c.crVersionMatchesPodsVersion)
 
 	// Only if cluster is paused, allow resuming it. All other modifications are forbinden.
-	if params.Resume && clusterState == ClusterStatePaused {
+	if params.Resume && canResume(clusterState, cluster.Spec.Pause) {
 		cluster.Spec.Pause = false
+		if err = c.recordOperation(&cluster.ObjectMeta, OperationUpdate); err != nil {
+			return err
+		}
 		return c.kube.Apply(ctx, cluster)
 	}
 
@@ -573,6 +1397,13 @@ func (c *K8sClient) UpdatePXCCluster(ctx context.Context, params *PXCParams) err
 		} else {
 			cluster.Spec.HAProxy.Size = params.Size
 		}
+		// Operator rejects small clusters unless unsafe config is allowed.
+		if params.AllowUnsafeConfig == nil && pxcAllowUnsafeConfig(params) {
+			cluster.Spec.AllowUnsafeConfig = true
+		}
+	}
+	if params.AllowUnsafeConfig != nil {
+		cluster.Spec.AllowUnsafeConfig = *params.AllowUnsafeConfig
 	}
 
 	if params.PXC != nil {
@@ -595,6 +1426,9 @@ func (c *K8sClient) UpdatePXCCluster(ctx context.Context, params *PXCParams) err
 		cluster.Spec.HAProxy.Resources = c.updateComputeResources(params.HAProxy.ComputeResources, cluster.Spec.HAProxy.Resources)
 	}
 
+	if err = c.recordOperation(&cluster.ObjectMeta, OperationUpdate); err != nil {
+		return err
+	}
 	patch, err := json.Marshal(cluster)
 	if err != nil {
 		return err
@@ -603,8 +1437,39 @@ func (c *K8sClient) UpdatePXCCluster(ctx context.Context, params *PXCParams) err
 	return err
 }
 
+// pxcAllowUnsafeConfig returns whether operator's safety checks should be disabled for the cluster.
+func pxcAllowUnsafeConfig(params *PXCParams) bool {
+	if params.AllowUnsafeConfig != nil {
+		return *params.AllowUnsafeConfig
+	}
+	return params.Size < 3
+}
+
 // DeletePXCCluster deletes Percona XtraDB cluster with provided name.
-func (c *K8sClient) DeletePXCCluster(ctx context.Context, name string) error {
+// It returns ErrBackupInProgress if cluster has backups which are not finished yet, unless force is true.
+// ErrNotFound is returned if cluster doesn't exist, leftover secrets are deleted anyway.
+// Callers retrying deletion should treat ErrNotFound as success: a repeated call returns it.
+func (c *K8sClient) DeletePXCCluster(ctx context.Context, name string, force bool) error {
+	ctx = c.withRequestLogger(ctx, "DeletePXCCluster", name)
+	l := c.requestLogger(ctx)
+	l.Debug("deleting cluster")
+
+	cluster, err := c.kube.GetPXCCluster(ctx, name)
+	if err != nil && !apiErrors.IsNotFound(err) {
+		return errors.Wrap(err, "cannot get PXC cluster")
+	}
+	exists := err == nil
+
+	if exists && !force {
+		backups, err := c.kube.ListPXCClusterBackups(ctx)
+		if err != nil {
+			return errors.Wrap(err, "cannot list PXC backups")
+		}
+		if active := activePXCBackups(backups.Items, name); len(active) != 0 {
+			return errors.Wrapf(ErrBackupInProgress, "%s", strings.Join(active, ", "))
+		}
+	}
+
 	spec := &pxcv1.PerconaXtraDBCluster{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: pxcAPINamespace + "/v1",
@@ -614,24 +1479,170 @@ func (c *K8sClient) DeletePXCCluster(ctx context.Context, name string) error {
 			Name: name,
 		},
 	}
-	err := c.kube.Delete(ctx, spec)
-	if err != nil {
-		return errors.Wrap(err, "cannot delete PXC")
-	}
+	if exists {
+		// Deletion is recorded for the time cluster is terminating, failure to record it doesn't stop deletion.
+		patch, err := c.historyPatch(&cluster.ObjectMeta, OperationDelete)
+		if err == nil {
+			_, err = c.kube.PatchPXCCluster(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{})
+		}
+		if err != nil {
+			l.Errorf("cannot record deletion of %s: %v", name, err)
+		}
 
-	err = c.deleteSecret(ctx, fmt.Sprintf(pxcSecretNameTmpl, name))
+		err = c.kube.Delete(ctx, spec)
+		if err != nil {
+			return errors.Wrap(err, "cannot delete PXC")
+		}
+	}
+
+	err = c.deleteSecret(ctx, fmt.Sprintf(pxcSecretNameTmpl, name))
 	if err != nil {
-		c.l.Errorf("cannot delete secret for %s: %v", name, err)
+		l.Errorf("cannot delete secret for %s: %v", name, err)
 	}
 
 	err = c.deleteSecret(ctx, fmt.Sprintf(pxcInternalSecretTmpl, name))
 	if err != nil {
-		c.l.Errorf("cannot delete internal secret for %s: %v", name, err)
+		l.Errorf("cannot delete internal secret for %s: %v", name, err)
+	}
+
+	if !exists {
+		return errors.Wrapf(ErrNotFound, "PXC cluster %q", name)
+	}
+	return nil
+}
+
+// ForceDeletePXCCluster deletes Percona XtraDB cluster even if it's stuck terminating.
+// If cluster is not gone after a timeout, finalizers added by dbaas-controller are removed
+// and PVCs of the cluster may be left behind.
+func (c *K8sClient) ForceDeletePXCCluster(ctx context.Context, name string) error {
+	ctx = c.withRequestLogger(ctx, "ForceDeletePXCCluster", name)
+	l := c.requestLogger(ctx)
+
+	if err := c.DeletePXCCluster(ctx, name, true); err != nil {
+		return err
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, forceDeleteTimeout)
+	defer cancel()
+	var cluster *pxcv1.PerconaXtraDBCluster
+	err := wait.PollImmediateUntilWithContext(waitCtx, 5*time.Second, func(ctx context.Context) (bool, error) {
+		var err error
+		cluster, err = c.kube.GetPXCCluster(ctx, name)
+		if apiErrors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, nil //nolint:nilerr
+	})
+	if err == nil {
+		return nil
+	}
+	if cluster == nil {
+		return errors.Wrap(err, "cannot get PXC cluster")
 	}
 
+	l.Warnf("cluster is still terminating after %s, removing finalizers %v: persistent volume claims may be orphaned",
+		forceDeleteTimeout, cluster.Finalizers)
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"finalizers":      withoutFinalizers(cluster.Finalizers, pxcProxySQLPVCFinalizer, pxcPVCFinalizer),
+			"resourceVersion": cluster.ResourceVersion,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = c.kube.PatchPXCCluster(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{})
+	if err != nil && !apiErrors.IsNotFound(err) {
+		return errors.Wrap(err, "cannot remove PXC cluster finalizers")
+	}
 	return nil
 }
 
+// withoutFinalizers returns finalizers except removed ones, it's never nil so they are cleared by merge patch.
+func withoutFinalizers(finalizers []string, removed ...string) []string {
+	skip := make(map[string]struct{}, len(removed))
+	for _, f := range removed {
+		skip[f] = struct{}{}
+	}
+	res := make([]string, 0, len(finalizers))
+	for _, f := range finalizers {
+		if _, ok := skip[f]; !ok {
+			res = append(res, f)
+		}
+	}
+	return res
+}
+
+// applyWithRetry applies object retrying on errors of admission webhooks which are not ready yet,
+// e.g. right after operator installation.
+func (c *K8sClient) applyWithRetry(ctx context.Context, obj runtime.Object) error {
+	return retryOnTransientWebhookError(ctx, applyRetryBackoff, func() error {
+		err := c.kube.Apply(ctx, obj)
+		if isTransientWebhookError(err) {
+			c.requestLogger(ctx).Debugf("admission webhook is not ready, retrying: %v", err)
+		}
+		return err
+	})
+}
+
+// retryOnTransientWebhookError calls f with backoff while it returns transient webhook errors.
+// Waiting is interrupted when ctx is canceled, ctx error is returned then.
+// The last error of f is returned if backoff is exhausted.
+func retryOnTransientWebhookError(ctx context.Context, backoff wait.Backoff, f func() error) error {
+	var lastErr error
+	err := wait.ExponentialBackoffWithContext(ctx, backoff, func() (bool, error) {
+		lastErr = f()
+		if isTransientWebhookError(lastErr) {
+			return false, nil
+		}
+		return true, lastErr
+	})
+	if errors.Is(err, wait.ErrWaitTimeout) {
+		return lastErr
+	}
+	return err
+}
+
+// isTransientWebhookError returns true if error is caused by admission webhook service which is not available yet.
+func isTransientWebhookError(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := err.Error()
+	if !strings.Contains(msg, "failed calling webhook") {
+		return false
+	}
+	for _, reason := range []string{
+		"connection refused",
+		"no endpoints available",
+		"not found",
+		"i/o timeout",
+		"context deadline exceeded",
+		"EOF",
+	} {
+		if strings.Contains(msg, reason) {
+			return true
+		}
+	}
+	return false
+}
+
+// activePXCBackups returns names of backups of given cluster which are neither succeeded nor failed.
+func activePXCBackups(backups []pxcv1.PerconaXtraDBClusterBackup, clusterName string) []string {
+	var active []string
+	for _, backup := range backups {
+		if backup.Spec.PXCCluster != clusterName {
+			continue
+		}
+		switch backup.Status.State {
+		case pxcv1.BackupSucceeded, pxcv1.BackupFailed:
+			continue
+		}
+		active = append(active, backup.Name)
+	}
+	return active
+}
+
 func (c *K8sClient) deleteSecret(ctx context.Context, secretName string) error {
 	secret := &corev1.Secret{
 		TypeMeta: metav1.TypeMeta{
@@ -648,6 +1659,7 @@ func (c *K8sClient) deleteSecret(ctx context.Context, secretName string) error {
 
 // GetPXCClusterCredentials returns an PXC cluster credentials.
 func (c *K8sClient) GetPXCClusterCredentials(ctx context.Context, name string) (*PXCCredentials, error) {
+	ctx = c.withRequestLogger(ctx, "GetPXCClusterCredentials", name)
 	cluster, err := c.kube.GetPXCCluster(ctx, name)
 	if err != nil {
 		if apiErrors.IsNotFound(err) {
@@ -658,19 +1670,24 @@ func (c *K8sClient) GetPXCClusterCredentials(ctx context.Context, name string) (
 
 	clusterInfo := kube.NewDBClusterInfoFromPXC(cluster)
 	clusterState := c.getClusterState(ctx, clusterInfo, c.crVersionMatchesPodsVersion)
-	if clusterState != ClusterStateReady && clusterState != ClusterStateChanging {
+	if !credentialsAvailable(clusterState) {
 		return nil, errors.Wrapf(
 			errors.Wrap(ErrPXCClusterStateUnexpected,
 				fmt.Sprintf(canNotGetCredentialsErrTemplate, "XtraDb"),
 			),
-			"cluster state is state %v, %v or %v is expected",
+			"cluster state is %v, %v, %v or %v is expected",
 			clusterState,
 			ClusterStateReady,
 			ClusterStateChanging,
+			ClusterStateUpgrading,
 		)
 	}
 
-	secret, err := c.kube.GetSecret(ctx, fmt.Sprintf(pxcSecretNameTmpl, name))
+	return c.pxcCredentials(ctx, cluster)
+}
+
+func (c *K8sClient) pxcCredentials(ctx context.Context, cluster *pxcv1.PerconaXtraDBCluster) (*PXCCredentials, error) {
+	secret, err := c.kube.GetSecret(ctx, pxcSecretName(cluster))
 	if err != nil {
 		return nil, errors.Wrap(err, "cannot get XtraDb cluster secrets")
 	}
@@ -678,7 +1695,7 @@ func (c *K8sClient) GetPXCClusterCredentials(ctx context.Context, name string) (
 
 	credentials := &PXCCredentials{
 		Host:     cluster.Status.Host,
-		Port:     3306,
+		Port:     pxcPort,
 		Username: "root",
 		Password: password,
 	}
@@ -686,11 +1703,61 @@ func (c *K8sClient) GetPXCClusterCredentials(ctx context.Context, name string) (
 	return credentials, nil
 }
 
+// PXCClusterDescription contains PXC cluster status, endpoint and credentials taken at the same point in time.
+type PXCClusterDescription struct {
+	Cluster PXCCluster
+	Host    string
+	Port    int32
+	// Credentials are nil if cluster is not in the state allowing to get them.
+	Credentials *PXCCredentials
+}
+
+// DescribePXCCluster returns status of PXC cluster with provided name together with its endpoint and,
+// if cluster is ready, credentials. It saves a round trip compared to ListPXCClusters and GetPXCClusterCredentials.
+func (c *K8sClient) DescribePXCCluster(ctx context.Context, name string) (*PXCClusterDescription, error) {
+	ctx = c.withRequestLogger(ctx, "DescribePXCCluster", name)
+	cluster, err := c.kube.GetPXCCluster(ctx, name)
+	if err != nil {
+		if apiErrors.IsNotFound(err) {
+			return nil, errors.Wrapf(ErrNotFound, "PXC cluster %q", name)
+		}
+		return nil, errors.Wrap(err, "cannot get PXC cluster")
+	}
+
+	res := newPXCClusterDescription(cluster, c.toPXCCluster(ctx, cluster, c.crVersionMatchesPodsVersion))
+	if credentialsAvailable(res.Cluster.State) {
+		res.Credentials, err = c.pxcCredentials(ctx, cluster)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// newPXCClusterDescription returns description of PXC cluster with given status, without credentials.
+func newPXCClusterDescription(cluster *pxcv1.PerconaXtraDBCluster, status PXCCluster) *PXCClusterDescription {
+	return &PXCClusterDescription{
+		Cluster: status,
+		Host:    cluster.Status.Host,
+		Port:    pxcPort,
+	}
+}
+
+// pxcSecretName returns name of the secret with PXC cluster users passwords.
+// Secret name set in the spec (e.g. by CR template) takes precedence over the default one.
+func pxcSecretName(cluster *pxcv1.PerconaXtraDBCluster) string {
+	if cluster.Spec.SecretsName != "" {
+		return cluster.Spec.SecretsName
+	}
+	return fmt.Sprintf(pxcSecretNameTmpl, cluster.Name)
+}
+
 // GetKubernetesClusterType returns k8s cluster type based on storage class.
 func (c *K8sClient) GetKubernetesClusterType(ctx context.Context) KubernetesClusterType {
+	ctx = c.withRequestLogger(ctx, "GetKubernetesClusterType", "")
 	sc, err := c.kube.GetStorageClasses(ctx)
 	if err != nil {
-		c.l.Error(errors.Wrap(err, "failed to get k8s cluster type"))
+		c.requestLogger(ctx).Error(errors.Wrap(err, "failed to get k8s cluster type"))
 		return clusterTypeUnknown
 	}
 
@@ -702,6 +1769,12 @@ func (c *K8sClient) GetKubernetesClusterType(ctx context.Context) KubernetesClus
 		if strings.Contains(class.Provisioner, "aws") {
 			return AmazonEKSClusterType
 		}
+		if strings.Contains(class.Provisioner, "gce-pd") || strings.Contains(class.Provisioner, "gke.io") {
+			return GoogleGKEClusterType
+		}
+		if strings.Contains(class.Provisioner, "azure") {
+			return AzureAKSClusterType
+		}
 		if strings.Contains(class.Provisioner, "minikube") || strings.Contains(class.Provisioner, "kubevirt.io/hostpath-provisioner") || strings.Contains(class.Provisioner, "standard") {
 			return MinikubeClusterType
 		}
@@ -713,7 +1786,9 @@ func (c *K8sClient) GetKubernetesClusterType(ctx context.Context) KubernetesClus
 // RestartPXCCluster restarts Percona XtraDB cluster with provided name.
 // FIXME: https://jira.percona.com/browse/PMM-6980
 func (c *K8sClient) RestartPXCCluster(ctx context.Context, name string) error {
-	c.l.Info(name)
+	ctx = c.withRequestLogger(ctx, "RestartPXCCluster", name)
+	l := c.requestLogger(ctx)
+	l.Info("restarting cluster")
 	_, err := c.kube.RestartStatefulSet(ctx, name+"-"+"pxc")
 	if err != nil {
 		return err
@@ -731,79 +1806,153 @@ func (c *K8sClient) RestartPXCCluster(ctx context.Context, name string) error {
 }
 
 // getPerconaXtraDBClusters returns Percona XtraDB clusters.
-func (c *K8sClient) getPerconaXtraDBClusters(ctx context.Context) ([]PXCCluster, error) {
+func (c *K8sClient) getPerconaXtraDBClusters(ctx context.Context, managedOnly bool) ([]PXCCluster, error) {
 	list, err := c.kube.ListPXCClusters(ctx)
 	if err != nil {
 		return nil, errors.Wrap(err, "couldn't get Percona XtraDB clusters")
 	}
+	if managedOnly {
+		items := list.Items[:0]
+		for _, cluster := range list.Items {
+			if isManagedByDBaaS(&cluster.ObjectMeta) {
+				items = append(items, cluster)
+			}
+		}
+		list.Items = items
+	}
 
 	res := make([]PXCCluster, len(list.Items))
-	for i, cluster := range list.Items {
-		val := PXCCluster{
-			Name: cluster.Name,
-			Size: cluster.Spec.PXC.Size,
-			PXC: &PXC{
-				Image:            cluster.Spec.PXC.Image,
-				DiskSize:         c.getPXCDiskSize(cluster.Spec.PXC.VolumeSpec),
-				ComputeResources: c.getComputeResources(cluster.Spec.PXC.Resources),
-			},
-			Pause: cluster.Spec.Pause,
-		}
-		if len(cluster.Status.Conditions) > 0 {
-			val.DetailedState = []appStatus{
-				//{size: cluster.Status.Size, ready: cluster.Status.PMM.Status == "ready"},
-				{size: cluster.Status.HAProxy.Size, ready: cluster.Status.HAProxy.Ready},
-				{size: cluster.Status.ProxySQL.Size, ready: cluster.Status.ProxySQL.Ready},
-				{size: cluster.Status.PXC.Size, ready: cluster.Status.PXC.Ready},
-			}
-			val.Message = strings.Join(cluster.Status.Messages, ";")
+	crAndPodsMatchFunc := c.newBatchCRVersionMatcher("app.kubernetes.io/component=pxc", "app.kubernetes.io/instance")
+	g, gCtx := errgroup.WithContext(ctx)
+	g.SetLimit(listClustersConcurrency)
+	for i := range list.Items {
+		i := i
+		g.Go(func() error {
+			res[i] = c.toPXCCluster(gCtx, &list.Items[i], crAndPodsMatchFunc)
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// GetPXCClusterByUID returns Percona XtraDB cluster with given custom resource UID.
+// Unlike name, UID is not reused by a cluster recreated after deletion.
+func (c *K8sClient) GetPXCClusterByUID(ctx context.Context, uid string) (*PXCCluster, error) {
+	ctx = c.withRequestLogger(ctx, "GetPXCClusterByUID", "")
+	list, err := c.kube.ListPXCClusters(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "couldn't get Percona XtraDB clusters")
+	}
+	cluster := findPXCClusterByUID(list.Items, uid)
+	if cluster == nil {
+		return nil, errors.Wrapf(ErrNotFound, "PXC cluster with UID %q", uid)
+	}
+	res := c.toPXCCluster(ctx, cluster, c.crVersionMatchesPodsVersion)
+	return &res, nil
+}
+
+func findPXCClusterByUID(clusters []pxcv1.PerconaXtraDBCluster, uid string) *pxcv1.PerconaXtraDBCluster {
+	for i := range clusters {
+		if string(clusters[i].UID) == uid {
+			return &clusters[i]
+		}
+	}
+	return nil
+}
+
+// toPXCCluster converts PXC custom resource to PXCCluster.
+func (c *K8sClient) toPXCCluster(
+	ctx context.Context,
+	cluster *pxcv1.PerconaXtraDBCluster,
+	crAndPodsMatchFunc func(context.Context, kube.DBCluster) (bool, error),
+) PXCCluster {
+	val := PXCCluster{
+		Name:      cluster.Name,
+		UID:       string(cluster.UID),
+		Size:      cluster.Spec.PXC.Size,
+		CreatedAt: cluster.CreationTimestamp.Time,
+		PXC: &PXC{
+			Image:            cluster.Spec.PXC.Image,
+			DiskSize:         c.getPXCDiskSize(cluster.Spec.PXC.VolumeSpec),
+			ComputeResources: c.getComputeResources(cluster.Spec.PXC.Resources),
+		},
+		Pause: cluster.Spec.Pause,
+	}
+	if len(cluster.Status.Conditions) > 0 {
+		val.DetailedState = []appStatus{
+			//{size: cluster.Status.Size, ready: cluster.Status.PMM.Status == "ready"},
+			{size: cluster.Status.HAProxy.Size, ready: cluster.Status.HAProxy.Ready},
+			{size: cluster.Status.ProxySQL.Size, ready: cluster.Status.ProxySQL.Ready},
+			{size: cluster.Status.PXC.Size, ready: cluster.Status.PXC.Ready},
 		}
+		val.Message = strings.Join(cluster.Status.Messages, ";")
+	}
 
-		clusterInfo := kube.NewDBClusterInfoFromPXC(&cluster)
+	clusterInfo := kube.NewDBClusterInfoFromPXC(cluster)
 
-		val.State = c.getClusterState(ctx, clusterInfo, c.crVersionMatchesPodsVersion)
-		if cluster.Spec.ProxySQL != nil {
-			val.ProxySQL = &ProxySQL{
-				DiskSize:         c.getPXCDiskSize(cluster.Spec.ProxySQL.VolumeSpec),
-				ComputeResources: c.getComputeResources(cluster.Spec.ProxySQL.Resources),
-			}
-			val.Exposed = cluster.Spec.ProxySQL.ServiceType != "" &&
-				cluster.Spec.ProxySQL.ServiceType != corev1.ServiceTypeClusterIP
-			res[i] = val
-			continue
+	val.State = c.getClusterState(ctx, clusterInfo, crAndPodsMatchFunc)
+	if cluster.Spec.ProxySQL != nil {
+		val.ProxySQL = &ProxySQL{
+			DiskSize:         c.getPXCDiskSize(cluster.Spec.ProxySQL.VolumeSpec),
+			ComputeResources: c.getComputeResources(cluster.Spec.ProxySQL.Resources),
 		}
-		if cluster.Spec.HAProxy != nil {
-			val.HAProxy = &HAProxy{
-				ComputeResources: c.getComputeResources(cluster.Spec.HAProxy.Resources),
-			}
-			val.Exposed = cluster.Spec.HAProxy.ServiceType != "" &&
-				cluster.Spec.HAProxy.ServiceType != corev1.ServiceTypeClusterIP
+		val.Exposed = c.isPXCProxyExposed(ctx, cluster.Name+"-proxysql", cluster.Spec.ProxySQL.ServiceType)
+		return val
+	}
+	if cluster.Spec.HAProxy != nil {
+		val.HAProxy = &HAProxy{
+			ComputeResources: c.getComputeResources(cluster.Spec.HAProxy.Resources),
 		}
-		res[i] = val
+		val.Exposed = c.isPXCProxyExposed(ctx, cluster.Name+"-haproxy", cluster.Spec.HAProxy.ServiceType)
 	}
-	return res, nil
+	return val
+}
+
+// isPXCProxyExposed checks whether proxy service of PXC cluster is exposed.
+// Spec field is not always set (e.g. expose is configured via defaults), so the actual service type takes precedence.
+func (c *K8sClient) isPXCProxyExposed(ctx context.Context, serviceName string, specServiceType corev1.ServiceType) bool {
+	service, err := c.kube.GetService(ctx, serviceName)
+	if err != nil {
+		if !apiErrors.IsNotFound(err) {
+			c.requestLogger(ctx).Warnf("failed to get service %q: %v", serviceName, err)
+		}
+		service = nil
+	}
+	return isExposed(specServiceType, service)
+}
+
+// isExposed returns true if given service, or spec service type when service is not created yet, is reachable from outside of cluster.
+func isExposed(specServiceType corev1.ServiceType, service *corev1.Service) bool {
+	serviceType := specServiceType
+	if service != nil {
+		serviceType = service.Spec.Type
+	}
+	return serviceType != "" && serviceType != corev1.ServiceTypeClusterIP
 }
 
 func (c *K8sClient) getClusterState(ctx context.Context, cluster kube.DBCluster, crAndPodsMatchFunc func(context.Context, kube.DBCluster) (bool, error)) ClusterState {
-	state := cluster.State
+	state := cluster.State()
 	if state == appStateUnknown {
 		return ClusterStateInvalid
 	}
 	// Handle paused state for operator version >= 1.9.0 and for operator version <= 1.8.0.
-	if state == appStatePaused || (cluster.Pause && state == appStateReady) {
+	if state == appStatePaused || (cluster.Paused() && state == appStateReady) {
 		return ClusterStatePaused
 	}
 
 	clusterState, ok := clusterStatesMap[string(state)]
 	if !ok {
-		c.l.Warnf("failed to recognize cluster state: %q, setting status to ClusterStateChanging", state)
+		c.requestLogger(ctx).Warnf("failed to recognize cluster state: %q, setting status to ClusterStateChanging", state)
 		return ClusterStateChanging
 	}
 	if clusterState == ClusterStateChanging {
 		// Check if cr and pods version matches.
 		match, err := crAndPodsMatchFunc(ctx, cluster)
 		if err != nil {
-			c.l.Warnf("failed to check if cluster %q is upgrading: %v", cluster.Name, err)
+			c.requestLogger(ctx).Warnf("failed to check if cluster %q is upgrading: %v", cluster.Name(), err)
 			return ClusterStateInvalid
 		}
 		if match {
@@ -871,7 +2020,17 @@ func (c *K8sClient) getDeletingPXCClusters(ctx context.Context, clusters []PXCCl
 
 // ListPSMDBClusters returns list of psmdb clusters and their statuses.
 func (c *K8sClient) ListPSMDBClusters(ctx context.Context) ([]PSMDBCluster, error) {
-	clusters, err := c.getPSMDBClusters(ctx)
+	return c.listPSMDBClusters(c.withRequestLogger(ctx, "ListPSMDBClusters", ""), false)
+}
+
+// ListManagedPSMDBClusters returns list of psmdb clusters created by dbaas-controller and their statuses.
+// Clusters being deleted are always included: their custom resources, and so the label, are already gone.
+func (c *K8sClient) ListManagedPSMDBClusters(ctx context.Context) ([]PSMDBCluster, error) {
+	return c.listPSMDBClusters(c.withRequestLogger(ctx, "ListManagedPSMDBClusters", ""), true)
+}
+
+func (c *K8sClient) listPSMDBClusters(ctx context.Context, managedOnly bool) ([]PSMDBCluster, error) {
+	clusters, err := c.getPSMDBClusters(ctx, managedOnly)
 	if err != nil {
 		return nil, errors.Wrap(err, "cannot get PSMDB clusters")
 	}
@@ -887,10 +2046,67 @@ func (c *K8sClient) ListPSMDBClusters(ctx context.Context) ([]PSMDBCluster, erro
 
 // CreatePSMDBCluster creates percona server for mongodb cluster with provided parameters.
 func (c *K8sClient) CreatePSMDBCluster(ctx context.Context, params *PSMDBParams) error {
-	_, err := c.kube.GetPSMDBCluster(ctx, params.Name)
+	ctx = c.withRequestLogger(ctx, "CreatePSMDBCluster", params.Name)
+	l := c.requestLogger(ctx)
+	l.Debug("creating cluster")
+
+	err := validateClusterSize(params.Size, params.AllowUnsafe)
+	if err != nil {
+		return err
+	}
+	if params.ConfigServerSize < 0 {
+		return errors.Wrapf(ErrInvalidConfigServerSize, "%d replicas", params.ConfigServerSize)
+	}
+	err = validateClusterSize(params.ConfigServerSize, params.AllowUnsafe)
+	if err != nil {
+		return errors.Wrap(err, "config server")
+	}
+	err = validatePMMParams(params.PMM)
+	if err != nil {
+		return err
+	}
+	err = validateComputeResources(params.BackupResources, "backup-agent")
+	if err != nil {
+		return err
+	}
+	err = validateEncryptionParams(params.Encryption)
+	if err != nil {
+		return err
+	}
+	err = validateSecurityContext(params.SecurityContext)
+	if err != nil {
+		return err
+	}
+	err = validateProfilingParams(params)
+	if err != nil {
+		return err
+	}
+	err = validateClusterDomain(params.ClusterDomain)
+	if err != nil {
+		return err
+	}
+	err = validateReplsetName(params.ReplsetName)
+	if err != nil {
+		return err
+	}
+	err = validateBackupCompression(params.BackupCompression)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.kube.GetPSMDBCluster(ctx, params.Name)
 	if err == nil {
 		return fmt.Errorf(clusterWithSameNameExistsErrTemplate, params.Name)
 	}
+	if err = c.checkOperatorNotUpgrading(ctx, psmdbOperatorDeploymentName); err != nil {
+		return err
+	}
+	if err = c.validateServiceAccount(ctx, params.BackupServiceAccount); err != nil {
+		return err
+	}
+	if err = c.validatePriorityClass(ctx, params.PriorityClassName); err != nil {
+		return err
+	}
 
 	extra := extraCRParams{}
 	extra.secretName = fmt.Sprintf(psmdbSecretNameTmpl, params.Name)
@@ -915,6 +2131,11 @@ func (c *K8sClient) CreatePSMDBCluster(ctx context.Context, params *PSMDBParams)
 				Enabled:    true,
 				ExposeType: corev1.ServiceTypeLoadBalancer,
 			}
+			if params.ExposeInternal {
+				if extra.expose.ServiceAnnotations, err = internalExposeAnnotations(clusterType); err != nil {
+					return err
+				}
+			}
 		}
 	} else {
 		// https://www.percona.com/doc/kubernetes-operator-for-psmongodb/minikube.html
@@ -959,11 +2180,35 @@ func (c *K8sClient) CreatePSMDBCluster(ctx context.Context, params *PSMDBParams)
 	}
 
 	if params.PMM != nil {
-		extra.secrets["PMM_SERVER_USER"] = []byte(params.PMM.Login)
-		extra.secrets["PMM_SERVER_PASSWORD"] = []byte(params.PMM.Password)
+		pmmSecrets, err := psmdbPMMSecrets(extra.operators.PsmdbOperatorVersion, params.PMM)
+		if err != nil {
+			return err
+		}
+		for k, v := range pmmSecrets {
+			extra.secrets[k] = v
+		}
 	}
 
-	spec, err := c.createPSMDBSpec(psmdbOperatorVersion, params, &extra)
+	spec, err := c.createPSMDBSpec(ctx, psmdbOperatorVersion, params, &extra)
+	if err != nil {
+		return err
+	}
+	// Arbiters may come from template or overrides, so votes are counted in the final spec.
+	if err = validatePSMDBExternalMembers(spec, params.ExternalMembers); err != nil {
+		return err
+	}
+	// Config servers and mongos may have their own resources, so the final spec is checked as well.
+	if !params.SkipCapacityCheck {
+		if err = c.validateNodeCapacity(ctx, params.NodeSelector, psmdbPodResources(spec)...); err != nil {
+			return err
+		}
+	}
+	if err = c.recordOperation(&spec.ObjectMeta, OperationCreate); err != nil {
+		return err
+	}
+	err = validateCRAPIVersion(
+		&spec.TypeMeta, c.getAPIVersionForPSMDBOperator(extra.operators.PsmdbOperatorVersion), extra.operators.apiVersions, psmdbAPINamespace,
+	)
 	if err != nil {
 		return err
 	}
@@ -972,11 +2217,15 @@ func (c *K8sClient) CreatePSMDBCluster(ctx context.Context, params *PSMDBParams)
 		return errors.Wrap(err, "cannot create secret for PXC")
 	}
 
-	return c.kube.Apply(ctx, spec)
+	return c.applyWithRetry(ctx, spec)
 }
 
 // UpdatePSMDBCluster changes size, stops, resumes or upgrades provided percona server for mongodb cluster.
 func (c *K8sClient) UpdatePSMDBCluster(ctx context.Context, params *PSMDBParams) error {
+	ctx = c.withRequestLogger(ctx, "UpdatePSMDBCluster", params.Name)
+	l := c.requestLogger(ctx)
+	l.Debug("updating cluster")
+
 	cluster, err := c.kube.GetPSMDBCluster(ctx, params.Name)
 	if err != nil {
 		return err
@@ -985,8 +2234,11 @@ func (c *K8sClient) UpdatePSMDBCluster(ctx context.Context, params *PSMDBParams)
 	cluster.APIVersion = psmdbAPINamespace + "/v1"
 	clusterInfo := kube.NewDBClusterInfoFromPSMDB(cluster)
 	clusterState := c.getClusterState(ctx, clusterInfo, c.crVersionMatchesPodsVersion)
-	if params.Resume && clusterState == ClusterStatePaused {
+	if params.Resume && canResume(clusterState, cluster.Spec.Pause) {
 		cluster.Spec.Pause = false
+		if err = c.recordOperation(&cluster.ObjectMeta, OperationUpdate); err != nil {
+			return err
+		}
 		return c.kube.Apply(ctx, cluster)
 	}
 
@@ -1011,8 +2263,21 @@ func (c *K8sClient) UpdatePSMDBCluster(ctx context.Context, params *PSMDBParams)
 		if err != nil {
 			return err
 		}
+		// Keep backup agent in line with the installed operator, otherwise it's left at the old version.
+		if params.BackupImage != "" {
+			cluster.Spec.Backup.Image = params.BackupImage
+		} else {
+			operators, err := c.CheckOperators(ctx)
+			if err != nil {
+				return err
+			}
+			cluster.Spec.Backup.Image = upgradedBackupImage(cluster.Spec.Backup.Image, operators.PsmdbOperatorVersion)
+		}
 		cluster.Spec.Image = params.Image
 	}
+	if err = c.recordOperation(&cluster.ObjectMeta, OperationUpdate); err != nil {
+		return err
+	}
 	patch, err := json.Marshal(cluster)
 	if err != nil {
 		return err
@@ -1025,6 +2290,26 @@ const (
 	updateStrategyRollingUpdate = "RollingUpdate"
 )
 
+// canResume returns true if cluster in given state can be resumed: it's either paused or still pausing,
+// in which case the operator reconciles cleared pause flag once it notices it.
+func canResume(state ClusterState, pauseRequested bool) bool {
+	return state == ClusterStatePaused || (state == ClusterStateChanging && pauseRequested)
+}
+
+// upgradedBackupImage returns default backup image of given operator version if image is a default
+// backup image of any operator version. Default backup images are tagged with operator version,
+// not server version. Other images are returned unchanged.
+func upgradedBackupImage(image, operatorVersion string) string {
+	if operatorVersion == "" {
+		return image
+	}
+	prefix, suffix, _ := strings.Cut(psmdbBackupImageTemplate, "%s")
+	if !strings.HasPrefix(image, prefix) || !strings.HasSuffix(image, suffix) || len(image) <= len(prefix)+len(suffix) {
+		return image
+	}
+	return fmt.Sprintf(psmdbBackupImageTemplate, operatorVersion)
+}
+
 func (c *K8sClient) validateImage(crImage, newImage string) error {
 	// Check that only tag changed.
 	newImageAndTag := strings.Split(newImage, ":")
@@ -1043,7 +2328,13 @@ func (c *K8sClient) validateImage(crImage, newImage string) error {
 }
 
 // DeletePSMDBCluster deletes percona server for mongodb cluster with provided name.
+// ErrNotFound is returned if cluster doesn't exist, leftover secrets are deleted anyway.
+// Callers retrying deletion should treat ErrNotFound as success: a repeated call returns it.
 func (c *K8sClient) DeletePSMDBCluster(ctx context.Context, name string) error {
+	ctx = c.withRequestLogger(ctx, "DeletePSMDBCluster", name)
+	l := c.requestLogger(ctx)
+	l.Debug("deleting cluster")
+
 	spec := &psmdbv1.PerconaServerMongoDB{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: psmdbAPINamespace + "/v1",
@@ -1053,88 +2344,885 @@ func (c *K8sClient) DeletePSMDBCluster(ctx context.Context, name string) error {
 			Name: name,
 		},
 	}
-	err := c.kube.Delete(ctx, spec)
+	// Cluster is read before deletion to find out if encryption key is managed by user.
+	cluster, err := c.kube.GetPSMDBCluster(ctx, name)
 	if err != nil {
-		return errors.Wrap(err, "cannot delete PSMDB")
+		if !apiErrors.IsNotFound(err) {
+			return errors.Wrap(err, "cannot get PSMDB cluster")
+		}
+		cluster = nil
+	}
+
+	if cluster != nil {
+		// Deletion is recorded for the time cluster is terminating, failure to record it doesn't stop deletion.
+		patch, err := c.historyPatch(&cluster.ObjectMeta, OperationDelete)
+		if err == nil {
+			_, err = c.kube.PatchPSMDBCluster(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{})
+		}
+		if err != nil {
+			l.Errorf("cannot record deletion of %s: %v", name, err)
+		}
+
+		err = c.kube.Delete(ctx, spec)
+		if err != nil {
+			return errors.Wrap(err, "cannot delete PSMDB")
+		}
 	}
 
 	err = c.deleteSecret(ctx, fmt.Sprintf(psmdbSecretNameTmpl, name))
 	if err != nil {
-		c.l.Errorf("cannot delete secret for %s: %v", name, err)
+		l.Errorf("cannot delete secret for %s: %v", name, err)
 	}
 
-	psmdbInternalSecrets := []string{"internal-%s-users", "%s-ssl", "%s-ssl-internal", "%s-mongodb-keyfile", "%s-mongodb-encryption-key"}
-
-	for _, secretTmpl := range psmdbInternalSecrets {
-		err = c.deleteSecret(ctx, fmt.Sprintf(secretTmpl, name))
+	for _, secretName := range psmdbInternalSecrets(name, cluster) {
+		err = c.deleteSecret(ctx, secretName)
 		if err != nil {
-			c.l.Errorf("cannot delete internal secret for %s: %v", name, err)
+			l.Errorf("cannot delete internal secret for %s: %v", name, err)
 		}
 	}
 
+	if cluster == nil {
+		return errors.Wrapf(ErrNotFound, "PSMDB cluster %q", name)
+	}
 	return nil
 }
 
+// psmdbInternalSecrets returns names of secrets created by the operator for the cluster.
+// Encryption key secret is not included if cluster references user-managed one.
+func psmdbInternalSecrets(name string, cluster *psmdbv1.PerconaServerMongoDB) []string {
+	secrets := []string{
+		fmt.Sprintf("internal-%s-users", name),
+		fmt.Sprintf("%s-ssl", name),
+		fmt.Sprintf("%s-ssl-internal", name),
+		fmt.Sprintf("%s-mongodb-keyfile", name),
+	}
+	encryptionKeySecret := fmt.Sprintf(psmdbEncryptionKeySecretTmpl, name)
+	if cluster != nil && cluster.Spec.Mongod != nil && cluster.Spec.Mongod.Security != nil &&
+		cluster.Spec.Mongod.Security.EncryptionKeySecret != "" &&
+		cluster.Spec.Mongod.Security.EncryptionKeySecret != encryptionKeySecret {
+		return secrets
+	}
+	return append(secrets, encryptionKeySecret)
+}
+
 // RestartPSMDBCluster restarts Percona server for mongodb cluster with provided name.
 // FIXME: https://jira.percona.com/browse/PMM-6980
 func (c *K8sClient) RestartPSMDBCluster(ctx context.Context, name string) error {
-	if _, err := c.kube.GetStatefulSet(ctx, name+"-rs0"); err == nil {
-		_, err = c.kube.RestartStatefulSet(ctx, name+"-rs0")
+	ctx = c.withRequestLogger(ctx, "RestartPSMDBCluster", name)
+	l := c.requestLogger(ctx)
+	l.Info("restarting cluster")
+	replset := psmdbDefaultReplsetName
+	if cluster, err := c.kube.GetPSMDBCluster(ctx, name); err == nil {
+		replset = psmdbReplsetName(cluster)
+	}
+	if _, err := c.kube.GetStatefulSet(ctx, name+"-"+replset); err == nil {
+		_, err = c.kube.RestartStatefulSet(ctx, name+"-"+replset)
 		return err
 	}
 	return nil
 }
 
-// GetPSMDBClusterCredentials returns a PSMDB cluster.
-func (c *K8sClient) GetPSMDBClusterCredentials(ctx context.Context, name string) (*PSMDBCredentials, error) {
-	cluster, err := c.kube.GetPSMDBCluster(ctx, name)
-	if err != nil {
-		if apiErrors.IsNotFound(err) {
-			return nil, errors.Wrap(ErrNotFound, fmt.Sprintf(canNotGetCredentialsErrTemplate, "PSMDB"))
-		}
-		return nil, errors.Wrap(err, fmt.Sprintf(canNotGetCredentialsErrTemplate, "PSMDB"))
+// EnablePMM enables monitoring of existing PXC or PSMDB cluster by PMM server.
+// PMM credentials are stored in the cluster secret.
+func (c *K8sClient) EnablePMM(ctx context.Context, clusterName string, pmm *PMM) error {
+	ctx = c.withRequestLogger(ctx, "EnablePMM", clusterName)
+	if pmm == nil {
+		return errors.New("PMM parameters are required")
 	}
-
-	clusterInfo := kube.NewDBClusterInfoFromPSMDB(cluster)
-
-	clusterState := c.getClusterState(ctx, clusterInfo, c.crVersionMatchesPodsVersion)
-	if clusterState != ClusterStateReady {
-		return nil, errors.Wrap(ErrPSMDBClusterNotReady, fmt.Sprintf(canNotGetCredentialsErrTemplate, "PSMDB"))
+	if err := validatePMMParams(pmm); err != nil {
+		return err
 	}
 
-	password := ""
-	username := ""
-	secret, err := c.kube.GetSecret(ctx, fmt.Sprintf(psmdbSecretNameTmpl, name))
+	pxcCluster, psmdbCluster, err := c.getDatabaseCluster(ctx, clusterName)
 	if err != nil {
-		return nil, errors.Wrap(err, "cannot get PSMDB cluster secrets")
+		return err
 	}
-	username = string(secret.Data["MONGODB_USER_ADMIN_USER"])
-	password = string(secret.Data["MONGODB_USER_ADMIN_PASSWORD"])
 
-	credentials := &PSMDBCredentials{
-		Username:   username,
-		Password:   password,
-		Host:       cluster.Status.Host,
-		Port:       27017,
-		Replicaset: "rs0",
+	if pxcCluster != nil {
+		secrets, err := pxcPMMSecrets(pxcCluster.Spec.CRVersion, pmm)
+		if err != nil {
+			return err
+		}
+		err = c.updateSecretData(ctx, pxcSecretName(pxcCluster), secrets)
+		if err != nil {
+			return errors.Wrap(err, "cannot update PMM credentials for PXC")
+		}
+		var current *corev1.ResourceRequirements
+		if pxcCluster.Spec.PMM != nil {
+			current = &pxcCluster.Spec.PMM.Resources
+		}
+		return c.patchPXCPMMSpec(ctx, clusterName, current, &pxcv1.PMMSpec{
+			Enabled:         true,
+			ServerHost:      pmm.PublicAddress,
+			ServerUser:      pmm.Login,
+			Image:           pmmClientImage,
+			ImagePullPolicy: corev1.PullPolicy(string(pullPolicy)),
+			Resources:       pmmClientResources(pmm.Resources),
+		})
 	}
 
-	return credentials, nil
+	secrets, err := psmdbPMMSecrets(psmdbCluster.Spec.CRVersion, pmm)
+	if err != nil {
+		return err
+	}
+	err = c.updateSecretData(ctx, psmdbSecretName(psmdbCluster), secrets)
+	if err != nil {
+		return errors.Wrap(err, "cannot update PMM credentials for PSMDB")
+	}
+	return c.patchPSMDBPMMSpec(ctx, clusterName, &psmdbCluster.Spec.PMM.Resources, &psmdbv1.PMMSpec{
+		Enabled:    true,
+		ServerHost: pmm.PublicAddress,
+		Image:      pmmClientImage,
+		Resources:  pmmClientResources(pmm.Resources),
+	})
 }
 
-func (c *K8sClient) crVersionMatchesPodsVersion(ctx context.Context, cluster kube.DBCluster) (bool, error) {
-	podLables := cluster.PodLabels
-	pods, err := c.GetPods(ctx, "", strings.Join(podLables, ","))
+// DisablePMM disables monitoring of existing PXC or PSMDB cluster by PMM server.
+func (c *K8sClient) DisablePMM(ctx context.Context, clusterName string) error {
+	ctx = c.withRequestLogger(ctx, "DisablePMM", clusterName)
+	pxcCluster, _, err := c.getDatabaseCluster(ctx, clusterName)
 	if err != nil {
-		return false, err
+		return err
 	}
-	if len(pods.Items) == 0 {
-		// Avoid stating it versions don't match when there are no pods to check.
-		return true, nil
+	// enabled field is omitted if false, so it is set explicitly.
+	disabled := map[string]interface{}{"enabled": false}
+	if pxcCluster != nil {
+		return c.patchPXCPMMSpec(ctx, clusterName, nil, disabled)
 	}
-	images := make(map[string]struct{})
-	for _, p := range pods.Items {
-		for _, containerName := range cluster.ContainerNames {
+	return c.patchPSMDBPMMSpec(ctx, clusterName, nil, disabled)
+}
+
+// PMMStatus contains PMM configuration of a cluster as it is set in the custom resource spec.
+type PMMStatus struct {
+	Enabled    bool
+	ServerHost string
+	Image      string
+}
+
+// GetPMMStatus returns PMM configuration of PXC or PSMDB cluster.
+func (c *K8sClient) GetPMMStatus(ctx context.Context, clusterName string) (*PMMStatus, error) {
+	ctx = c.withRequestLogger(ctx, "GetPMMStatus", clusterName)
+	pxcCluster, psmdbCluster, err := c.getDatabaseCluster(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+	if pxcCluster != nil {
+		return pxcPMMStatus(pxcCluster), nil
+	}
+	return psmdbPMMStatus(psmdbCluster), nil
+}
+
+func pxcPMMStatus(cluster *pxcv1.PerconaXtraDBCluster) *PMMStatus {
+	pmm := cluster.Spec.PMM
+	if pmm == nil {
+		return new(PMMStatus)
+	}
+	return &PMMStatus{Enabled: pmm.Enabled, ServerHost: pmm.ServerHost, Image: pmm.Image}
+}
+
+func psmdbPMMStatus(cluster *psmdbv1.PerconaServerMongoDB) *PMMStatus {
+	pmm := cluster.Spec.PMM
+	return &PMMStatus{Enabled: pmm.Enabled, ServerHost: pmm.ServerHost, Image: pmm.Image}
+}
+
+// ClusterCondition is a status condition of PXC or PSMDB custom resource.
+type ClusterCondition struct {
+	Type           string
+	Status         string
+	Reason         string
+	Message        string
+	LastTransition time.Time
+}
+
+// GetClusterConditions returns status conditions of PXC or PSMDB cluster in the order operator reported them.
+func (c *K8sClient) GetClusterConditions(ctx context.Context, name string) ([]ClusterCondition, error) {
+	ctx = c.withRequestLogger(ctx, "GetClusterConditions", name)
+	pxcCluster, psmdbCluster, err := c.getDatabaseCluster(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if pxcCluster != nil {
+		return pxcClusterConditions(pxcCluster), nil
+	}
+	return psmdbClusterConditions(psmdbCluster), nil
+}
+
+func pxcClusterConditions(cluster *pxcv1.PerconaXtraDBCluster) []ClusterCondition {
+	res := make([]ClusterCondition, 0, len(cluster.Status.Conditions))
+	for _, cond := range cluster.Status.Conditions {
+		res = append(res, ClusterCondition{
+			Type:           string(cond.Type),
+			Status:         string(cond.Status),
+			Reason:         cond.Reason,
+			Message:        cond.Message,
+			LastTransition: cond.LastTransitionTime.Time,
+		})
+	}
+	return res
+}
+
+func psmdbClusterConditions(cluster *psmdbv1.PerconaServerMongoDB) []ClusterCondition {
+	res := make([]ClusterCondition, 0, len(cluster.Status.Conditions))
+	for _, cond := range cluster.Status.Conditions {
+		res = append(res, ClusterCondition{
+			Type:           string(cond.Type),
+			Status:         string(cond.Status),
+			Reason:         cond.Reason,
+			Message:        cond.Message,
+			LastTransition: cond.LastTransitionTime.Time,
+		})
+	}
+	return res
+}
+
+// hasBeenReady returns true if any of conditions reports that cluster became ready.
+func hasBeenReady(conditions []ClusterCondition) bool {
+	for _, cond := range conditions {
+		if cond.Type == string(pxcv1.AppStateReady) && cond.Status == string(pxcv1.ConditionTrue) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetClusterImages returns images of PXC or PSMDB cluster components (pxc, proxysql, haproxy, mongod, backup, pmm)
+// as they are set in the custom resource spec. Components which are disabled are omitted.
+func (c *K8sClient) GetClusterImages(ctx context.Context, name string) (map[string]string, error) {
+	ctx = c.withRequestLogger(ctx, "GetClusterImages", name)
+	pxcCluster, psmdbCluster, err := c.getDatabaseCluster(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if pxcCluster != nil {
+		return pxcClusterImages(pxcCluster), nil
+	}
+	return psmdbClusterImages(psmdbCluster), nil
+}
+
+func pxcClusterImages(cluster *pxcv1.PerconaXtraDBCluster) map[string]string {
+	images := make(map[string]string)
+	add := func(component, image string) {
+		if image != "" {
+			images[component] = image
+		}
+	}
+	spec := cluster.Spec
+	if spec.PXC != nil && spec.PXC.PodSpec != nil {
+		add("pxc", spec.PXC.PodSpec.Image)
+	}
+	if spec.ProxySQL != nil && spec.ProxySQL.Enabled {
+		add("proxysql", spec.ProxySQL.Image)
+	}
+	if spec.HAProxy != nil && spec.HAProxy.Enabled {
+		add("haproxy", spec.HAProxy.Image)
+	}
+	if spec.Backup != nil {
+		add("backup", spec.Backup.Image)
+	}
+	if spec.PMM != nil && spec.PMM.Enabled {
+		add("pmm", spec.PMM.Image)
+	}
+	return images
+}
+
+func psmdbClusterImages(cluster *psmdbv1.PerconaServerMongoDB) map[string]string {
+	images := make(map[string]string)
+	add := func(component, image string) {
+		if image != "" {
+			images[component] = image
+		}
+	}
+	add("mongod", cluster.Spec.Image)
+	if cluster.Spec.Backup.Enabled {
+		add("backup", cluster.Spec.Backup.Image)
+	}
+	if cluster.Spec.PMM.Enabled {
+		add("pmm", cluster.Spec.PMM.Image)
+	}
+	return images
+}
+
+// getDatabaseCluster returns either PXC or PSMDB cluster with given name.
+func (c *K8sClient) getDatabaseCluster(ctx context.Context, name string) (*pxcv1.PerconaXtraDBCluster, *psmdbv1.PerconaServerMongoDB, error) {
+	pxcCluster, err := c.kube.GetPXCCluster(ctx, name)
+	if err == nil {
+		return pxcCluster, nil, nil
+	}
+	if !apiErrors.IsNotFound(err) {
+		return nil, nil, errors.Wrap(err, "cannot get PXC cluster")
+	}
+
+	psmdbCluster, err := c.kube.GetPSMDBCluster(ctx, name)
+	if err == nil {
+		return nil, psmdbCluster, nil
+	}
+	if apiErrors.IsNotFound(err) {
+		return nil, nil, errors.Wrapf(ErrNotFound, "cluster %q", name)
+	}
+	return nil, nil, errors.Wrap(err, "cannot get PSMDB cluster")
+}
+
+// RepairClusterSecret recreates missing secret with users passwords of existing PXC or PSMDB cluster.
+// Passwords are restored from operator's internal secret if it exists, since the database already uses them.
+// Otherwise new passwords are generated if cluster has never become ready, or ErrClusterPasswordsLost is returned,
+// as the database is initialized with passwords which can't be recovered. Nothing is changed if the secret exists.
+func (c *K8sClient) RepairClusterSecret(ctx context.Context, name string) error {
+	ctx = c.withRequestLogger(ctx, "RepairClusterSecret", name)
+	l := c.requestLogger(ctx)
+
+	pxcCluster, psmdbCluster, err := c.getDatabaseCluster(ctx, name)
+	if err != nil {
+		return err
+	}
+	var secretName, internalSecretName string
+	var generate func() (map[string][]byte, error)
+	var wasReady bool
+	if pxcCluster != nil {
+		secretName = pxcSecretName(pxcCluster)
+		internalSecretName = fmt.Sprintf(pxcInternalSecretTmpl, name)
+		generate = generatePXCPasswords
+		wasReady = pxcCluster.Status.Status == pxcv1.AppStateReady || hasBeenReady(pxcClusterConditions(pxcCluster))
+	} else {
+		secretName = psmdbSecretName(psmdbCluster)
+		internalSecretName = psmdbv1.InternalUserSecretName(psmdbCluster)
+		generate = generatePSMDBPasswords
+		wasReady = psmdbCluster.Status.State == psmdbv1.AppStateReady || hasBeenReady(psmdbClusterConditions(psmdbCluster))
+	}
+
+	_, err = c.kube.GetSecret(ctx, secretName)
+	if err == nil {
+		return nil
+	}
+	if !apiErrors.IsNotFound(err) {
+		return errors.Wrap(err, "cannot get cluster secret")
+	}
+
+	internal, err := c.kube.GetSecret(ctx, internalSecretName)
+	if err != nil {
+		if !apiErrors.IsNotFound(err) {
+			return errors.Wrap(err, "cannot get internal cluster secret")
+		}
+		internal = nil
+	}
+	data, err := repairedSecretData(internal, wasReady, generate)
+	if err != nil {
+		return errors.Wrapf(err, "secret %q", secretName)
+	}
+	l.Warnf("secret %q is missing, recreating it (restored from internal secret: %t)", secretName, internal != nil)
+	return c.CreateSecret(ctx, secretName, data)
+}
+
+// repairedSecretData returns data of recreated cluster secret: a copy of internal secret data if it's present,
+// or passwords returned by generate otherwise. Passwords are not generated for cluster which has been ready.
+func repairedSecretData(internal *corev1.Secret, wasReady bool, generate func() (map[string][]byte, error)) (map[string][]byte, error) {
+	if internal == nil || len(internal.Data) == 0 {
+		if wasReady {
+			return nil, errors.Wrap(ErrClusterPasswordsLost, "internal secret is missing")
+		}
+		return generate()
+	}
+	data := make(map[string][]byte, len(internal.Data))
+	for k, v := range internal.Data {
+		data[k] = v
+	}
+	return data, nil
+}
+
+// ResourceRef identifies Kubernetes object belonging to a cluster.
+type ResourceRef struct {
+	Kind      string
+	Name      string
+	Namespace string
+	Age       time.Duration
+}
+
+func newResourceRef(kind string, meta *metav1.ObjectMeta, now time.Time) ResourceRef {
+	return ResourceRef{Kind: kind, Name: meta.Name, Namespace: meta.Namespace, Age: now.Sub(meta.CreationTimestamp.Time)}
+}
+
+// GetClusterResources returns custom resource of PXC or PSMDB cluster and objects it owns:
+// secrets created by dbaas-controller or operator for the cluster, and services, statefulsets
+// and persistent volume claims labeled with the cluster name.
+func (c *K8sClient) GetClusterResources(ctx context.Context, name string) ([]ResourceRef, error) {
+	ctx = c.withRequestLogger(ctx, "GetClusterResources", name)
+	pxcCluster, psmdbCluster, err := c.getDatabaseCluster(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	var res []ResourceRef
+	var secrets []string
+	if pxcCluster != nil {
+		res = append(res, newResourceRef(kube.PXCKind, &pxcCluster.ObjectMeta, now))
+		secrets = []string{pxcSecretName(pxcCluster), fmt.Sprintf(pxcInternalSecretTmpl, name)}
+	} else {
+		res = append(res, newResourceRef(kube.PSMDBKind, &psmdbCluster.ObjectMeta, now))
+		secrets = []string{
+			psmdbSecretName(psmdbCluster),
+			psmdbv1.InternalUserSecretName(psmdbCluster),
+			fmt.Sprintf(psmdbEncryptionKeySecretTmpl, name),
+		}
+	}
+
+	for _, secretName := range secrets {
+		secret, err := c.kube.GetSecret(ctx, secretName)
+		if err != nil {
+			if apiErrors.IsNotFound(err) {
+				continue
+			}
+			return nil, errors.Wrapf(err, "cannot get secret %q", secretName)
+		}
+		res = append(res, newResourceRef(k8sMetaKindSecret, &secret.ObjectMeta, now))
+	}
+
+	selector := "app.kubernetes.io/instance=" + name
+	services, err := c.kube.ListServices(ctx, selector)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot list services")
+	}
+	for i := range services.Items {
+		res = append(res, newResourceRef("Service", &services.Items[i].ObjectMeta, now))
+	}
+	statefulSets, err := c.kube.ListStatefulSets(ctx, selector)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot list statefulsets")
+	}
+	for i := range statefulSets.Items {
+		res = append(res, newResourceRef("StatefulSet", &statefulSets.Items[i].ObjectMeta, now))
+	}
+	pvcs, err := c.kube.GetPersistentVolumeClaims(ctx, selector)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot list persistent volume claims")
+	}
+	for i := range pvcs.Items {
+		res = append(res, newResourceRef("PersistentVolumeClaim", &pvcs.Items[i].ObjectMeta, now))
+	}
+	return res, nil
+}
+
+// OperationType is a kind of change made by dbaas-controller on a cluster.
+type OperationType string
+
+const (
+	// OperationCreate represents cluster creation.
+	OperationCreate OperationType = "create"
+	// OperationUpdate represents cluster update, including suspending and resuming.
+	OperationUpdate OperationType = "update"
+	// OperationDelete represents cluster deletion.
+	OperationDelete OperationType = "delete"
+)
+
+// Operation is a change made by dbaas-controller on a cluster, recorded in custom resource annotation.
+type Operation struct {
+	Type      OperationType `json:"type"`
+	Timestamp time.Time     `json:"timestamp"`
+	// Requestor is the user dbaas-controller impersonates, or dbaas-controller itself.
+	Requestor string `json:"requestor"`
+}
+
+// GetClusterHistory returns operations made by dbaas-controller on PXC or PSMDB cluster, oldest first.
+// Only the last maxHistoryOperations operations are kept.
+func (c *K8sClient) GetClusterHistory(ctx context.Context, name string) ([]Operation, error) {
+	ctx = c.withRequestLogger(ctx, "GetClusterHistory", name)
+	pxcCluster, psmdbCluster, err := c.getDatabaseCluster(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if pxcCluster != nil {
+		return clusterHistory(&pxcCluster.ObjectMeta)
+	}
+	return clusterHistory(&psmdbCluster.ObjectMeta)
+}
+
+// requestor returns the user operations are made on behalf of.
+func (c *K8sClient) requestor() string {
+	if c.impersonateUser != "" {
+		return c.impersonateUser
+	}
+	return managedByValue
+}
+
+// recordOperation adds operation of given type to history annotation of custom resource.
+func (c *K8sClient) recordOperation(meta *metav1.ObjectMeta, opType OperationType) error {
+	return appendOperation(meta, Operation{Type: opType, Timestamp: time.Now().UTC().Truncate(time.Second), Requestor: c.requestor()})
+}
+
+// historyPatch returns merge patch adding operation of given type to history annotation of custom resource.
+func (c *K8sClient) historyPatch(meta *metav1.ObjectMeta, opType OperationType) ([]byte, error) {
+	m := meta.DeepCopy()
+	if err := c.recordOperation(m, opType); err != nil {
+		return nil, err
+	}
+	return json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{historyAnnotation: m.Annotations[historyAnnotation]},
+		},
+	})
+}
+
+func clusterHistory(meta *metav1.ObjectMeta) ([]Operation, error) {
+	history, ok := meta.Annotations[historyAnnotation]
+	if !ok {
+		return nil, nil
+	}
+	var ops []Operation
+	if err := json.Unmarshal([]byte(history), &ops); err != nil {
+		return nil, errors.Wrapf(err, "cannot parse %s annotation", historyAnnotation)
+	}
+	return ops, nil
+}
+
+// appendOperation adds operation to history annotation dropping the oldest ones above maxHistoryOperations.
+// Malformed history is replaced.
+func appendOperation(meta *metav1.ObjectMeta, op Operation) error {
+	ops, err := clusterHistory(meta)
+	if err != nil {
+		ops = nil
+	}
+	ops = append(ops, op)
+	if len(ops) > maxHistoryOperations {
+		ops = ops[len(ops)-maxHistoryOperations:]
+	}
+	history, err := json.Marshal(ops)
+	if err != nil {
+		return err
+	}
+	if meta.Annotations == nil {
+		meta.Annotations = make(map[string]string, 1)
+	}
+	meta.Annotations[historyAnnotation] = string(history)
+	return nil
+}
+
+// updateSecretData sets given keys of existing secret keeping other keys untouched.
+func (c *K8sClient) updateSecretData(ctx context.Context, secretName string, data map[string][]byte) error {
+	secret, err := c.kube.GetSecret(ctx, secretName)
+	if err != nil {
+		return err
+	}
+	if secret.Data == nil {
+		secret.Data = make(map[string][]byte, len(data))
+	}
+	for k, v := range data {
+		secret.Data[k] = v
+	}
+	return c.CreateSecret(ctx, secretName, secret.Data)
+}
+
+func (c *K8sClient) patchPXCPMMSpec(ctx context.Context, name string, current *corev1.ResourceRequirements, pmm interface{}) error {
+	patch, err := pmmSpecPatch(current, pmm)
+	if err != nil {
+		return err
+	}
+	_, err = c.kube.PatchPXCCluster(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
+
+func (c *K8sClient) patchPSMDBPMMSpec(ctx context.Context, name string, current *corev1.ResourceRequirements, pmm interface{}) error {
+	patch, err := pmmSpecPatch(current, pmm)
+	if err != nil {
+		return err
+	}
+	_, err = c.kube.PatchPSMDBCluster(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
+
+// pmmSpecPatch returns merge patch which replaces PMM spec of the cluster.
+// Merge patch merges nested objects, so current resources of PMM container missing in given spec
+// are set to null explicitly to be removed. Current resources may be nil if there are none.
+func pmmSpecPatch(current *corev1.ResourceRequirements, pmm interface{}) ([]byte, error) {
+	b, err := json.Marshal(pmm)
+	if err != nil {
+		return nil, err
+	}
+	var spec map[string]json.RawMessage
+	if err = json.Unmarshal(b, &spec); err != nil {
+		return nil, err
+	}
+	if resources, ok := spec["resources"]; ok && current != nil {
+		from, err := json.Marshal(current)
+		if err != nil {
+			return nil, err
+		}
+		if spec["resources"], err = jsonpatch.CreateMergePatch(from, resources); err != nil {
+			return nil, errors.Wrap(err, "cannot create PMM resources patch")
+		}
+	}
+	return json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{
+			"pmm": spec,
+		},
+	})
+}
+
+// GetPSMDBClusterCredentials returns a PSMDB cluster.
+func (c *K8sClient) GetPSMDBClusterCredentials(ctx context.Context, name string) (*PSMDBCredentials, error) {
+	ctx = c.withRequestLogger(ctx, "GetPSMDBClusterCredentials", name)
+	cluster, err := c.kube.GetPSMDBCluster(ctx, name)
+	if err != nil {
+		if apiErrors.IsNotFound(err) {
+			return nil, errors.Wrap(ErrNotFound, fmt.Sprintf(canNotGetCredentialsErrTemplate, "PSMDB"))
+		}
+		return nil, errors.Wrap(err, fmt.Sprintf(canNotGetCredentialsErrTemplate, "PSMDB"))
+	}
+
+	clusterInfo := kube.NewDBClusterInfoFromPSMDB(cluster)
+
+	clusterState := c.getClusterState(ctx, clusterInfo, c.crVersionMatchesPodsVersion)
+	if !credentialsAvailable(clusterState) {
+		return nil, errors.Wrapf(ErrPSMDBClusterNotReady, canNotGetCredentialsErrTemplate+", cluster state is %v, %v, %v or %v is expected",
+			"PSMDB", clusterState, ClusterStateReady, ClusterStateChanging, ClusterStateUpgrading)
+	}
+
+	return c.psmdbCredentials(ctx, cluster)
+}
+
+func (c *K8sClient) psmdbCredentials(ctx context.Context, cluster *psmdbv1.PerconaServerMongoDB) (*PSMDBCredentials, error) {
+	password := ""
+	username := ""
+	secret, err := c.kube.GetSecret(ctx, psmdbSecretName(cluster))
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot get PSMDB cluster secrets")
+	}
+	username = string(secret.Data["MONGODB_USER_ADMIN_USER"])
+	password = string(secret.Data["MONGODB_USER_ADMIN_PASSWORD"])
+
+	credentials := &PSMDBCredentials{
+		Username:   username,
+		Password:   password,
+		Host:       cluster.Status.Host,
+		Port:       psmdbPort(cluster),
+		Replicaset: psmdbReplsetName(cluster),
+	}
+
+	return credentials, nil
+}
+
+// psmdbReplsetName returns name of cluster's data replica set.
+func psmdbReplsetName(cluster *psmdbv1.PerconaServerMongoDB) string {
+	if len(cluster.Spec.Replsets) == 0 || cluster.Spec.Replsets[0] == nil || cluster.Spec.Replsets[0].Name == "" {
+		return psmdbDefaultReplsetName
+	}
+	return cluster.Spec.Replsets[0].Name
+}
+
+// psmdbConfigServerSize returns number of config server replicas.
+// Three config servers are recommended for any sharded cluster, but clusters of one or two nodes
+// are not highly available anyway, e.g. on minikube, so a single config server is used for them.
+func psmdbConfigServerSize(params *PSMDBParams) int32 {
+	if params.ConfigServerSize > 0 {
+		return params.ConfigServerSize
+	}
+	if params.Size > 0 && params.Size < psmdbDefaultConfigServerSize {
+		return 1
+	}
+	return psmdbDefaultConfigServerSize
+}
+
+// PSMDBClusterDescription contains PSMDB cluster status, endpoint and credentials taken at the same point in time.
+type PSMDBClusterDescription struct {
+	Cluster PSMDBCluster
+	Host    string
+	Port    int32
+	// Credentials are nil if cluster is not in the state allowing to get them.
+	Credentials *PSMDBCredentials
+}
+
+// DescribePSMDBCluster returns status of PSMDB cluster with provided name together with its endpoint and,
+// if cluster is ready, credentials. It saves a round trip compared to ListPSMDBClusters and GetPSMDBClusterCredentials.
+func (c *K8sClient) DescribePSMDBCluster(ctx context.Context, name string) (*PSMDBClusterDescription, error) {
+	ctx = c.withRequestLogger(ctx, "DescribePSMDBCluster", name)
+	cluster, err := c.kube.GetPSMDBCluster(ctx, name)
+	if err != nil {
+		if apiErrors.IsNotFound(err) {
+			return nil, errors.Wrapf(ErrNotFound, "PSMDB cluster %q", name)
+		}
+		return nil, errors.Wrap(err, "cannot get PSMDB cluster")
+	}
+
+	res := newPSMDBClusterDescription(cluster, c.toPSMDBCluster(ctx, cluster, c.crVersionMatchesPodsVersion))
+	if credentialsAvailable(res.Cluster.State) {
+		res.Credentials, err = c.psmdbCredentials(ctx, cluster)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// newPSMDBClusterDescription returns description of PSMDB cluster with given status, without credentials.
+func newPSMDBClusterDescription(cluster *psmdbv1.PerconaServerMongoDB, status PSMDBCluster) *PSMDBClusterDescription {
+	return &PSMDBClusterDescription{
+		Cluster: status,
+		Host:    cluster.Status.Host,
+		Port:    psmdbPort(cluster),
+	}
+}
+
+// psmdbSecretName returns name of the secret with PSMDB cluster users passwords.
+// Secret name set in the spec (e.g. by CR template) takes precedence over the default one.
+func psmdbSecretName(cluster *psmdbv1.PerconaServerMongoDB) string {
+	if cluster.Spec.Secrets != nil && cluster.Spec.Secrets.Users != "" {
+		return cluster.Spec.Secrets.Users
+	}
+	return fmt.Sprintf(psmdbSecretNameTmpl, cluster.Name)
+}
+
+// credentialsAvailable returns true if cluster credentials can be returned in given cluster state.
+// Credentials don't change while cluster is being changed or upgraded.
+func credentialsAvailable(state ClusterState) bool {
+	switch state {
+	case ClusterStateReady, ClusterStateChanging, ClusterStateUpgrading:
+		return true
+	default:
+		return false
+	}
+}
+
+// MemberRole is a role of PSMDB replica set member.
+type MemberRole string
+
+const (
+	// MemberRoleData is a data-bearing member, either primary or secondary.
+	// Current primary is known only to the replica set itself, neither CR status nor pods expose it.
+	MemberRoleData MemberRole = "data"
+	// MemberRoleNonVoting is a data-bearing member which doesn't take part in elections.
+	MemberRoleNonVoting MemberRole = "nonVoting"
+	// MemberRoleArbiter is a member which votes in elections but holds no data.
+	MemberRoleArbiter MemberRole = "arbiter"
+)
+
+// MemberEndpoint represents address of PSMDB replica set member.
+type MemberEndpoint struct {
+	Name    string
+	Replset string
+	Host    string
+	Port    int32
+	Role    MemberRole
+}
+
+// GetPSMDBMembers returns endpoints of all replica set members of PSMDB cluster, including config servers.
+// They are meant for direct connections, e.g. to route reads according to read preference.
+func (c *K8sClient) GetPSMDBMembers(ctx context.Context, name string) ([]MemberEndpoint, error) {
+	ctx = c.withRequestLogger(ctx, "GetPSMDBMembers", name)
+	cluster, err := c.kube.GetPSMDBCluster(ctx, name)
+	if err != nil {
+		if apiErrors.IsNotFound(err) {
+			return nil, errors.Wrapf(ErrNotFound, "PSMDB cluster %q", name)
+		}
+		return nil, errors.Wrap(err, "cannot get PSMDB cluster")
+	}
+	pods, err := c.GetPods(ctx, "", strings.Join(kube.NewDBClusterInfoFromPSMDB(cluster).DatabasePodLabels(), ","))
+	if err != nil {
+		return nil, err
+	}
+	return psmdbMembers(cluster, pods.Items), nil
+}
+
+// psmdbMembers returns replica set members endpoints for given cluster pods, mongos and other pods are skipped.
+// Host is built the same way the operator does it for not exposed replica sets.
+func psmdbMembers(cluster *psmdbv1.PerconaServerMongoDB, pods []corev1.Pod) []MemberEndpoint {
+	dnsSuffix := cluster.Spec.ClusterServiceDNSSuffix
+	if dnsSuffix == "" {
+		dnsSuffix = psmdbv1.DefaultDNSSuffix
+	}
+	port := int32(psmdbDefaultPort)
+	if cluster.Spec.Mongod != nil && cluster.Spec.Mongod.Net != nil && cluster.Spec.Mongod.Net.Port != 0 {
+		port = cluster.Spec.Mongod.Net.Port
+	}
+
+	members := make([]MemberEndpoint, 0, len(pods))
+	for _, pod := range pods {
+		replset := pod.Labels["app.kubernetes.io/replset"]
+		if replset == "" {
+			continue
+		}
+		role := MemberRoleData
+		switch pod.Labels["app.kubernetes.io/component"] {
+		case "mongos", "backup-schedule":
+			continue
+		case "arbiter":
+			role = MemberRoleArbiter
+		case "nonVoting":
+			role = MemberRoleNonVoting
+		}
+		members = append(members, MemberEndpoint{
+			Name:    pod.Name,
+			Replset: replset,
+			Host:    strings.Join([]string{pod.Name, cluster.Name + "-" + replset, pod.Namespace, dnsSuffix}, "."),
+			Port:    port,
+			Role:    role,
+		})
+	}
+	sort.Slice(members, func(i, j int) bool { return members[i].Name < members[j].Name })
+	return members
+}
+
+// psmdbPort returns port clients should connect to: mongos port for sharded clusters and mongod port otherwise.
+func psmdbPort(cluster *psmdbv1.PerconaServerMongoDB) int32 {
+	if cluster.Spec.Sharding.Enabled && cluster.Spec.Sharding.Mongos != nil && cluster.Spec.Sharding.Mongos.Port != 0 {
+		return cluster.Spec.Sharding.Mongos.Port
+	}
+	if !cluster.Spec.Sharding.Enabled && cluster.Spec.Mongod != nil && cluster.Spec.Mongod.Net != nil && cluster.Spec.Mongod.Net.Port != 0 {
+		return cluster.Spec.Mongod.Net.Port
+	}
+	return psmdbDefaultPort
+}
+
+func (c *K8sClient) crVersionMatchesPodsVersion(ctx context.Context, cluster kube.DBCluster) (bool, error) {
+	podLables := cluster.DatabasePodLabels()
+	pods, err := c.GetPods(ctx, "", strings.Join(podLables, ","))
+	if err != nil {
+		return false, err
+	}
+	return c.podsMatchCRImage(cluster, pods.Items), nil
+}
+
+// newBatchCRVersionMatcher returns the same check as crVersionMatchesPodsVersion, but pods matching selector
+// are listed only once, on the first call, and are distributed to clusters by value of instanceLabel.
+// It is meant to be used while listing clusters to avoid listing pods for every cluster.
+func (c *K8sClient) newBatchCRVersionMatcher(selector, instanceLabel string) func(context.Context, kube.DBCluster) (bool, error) {
+	var once sync.Once
+	var podsByCluster map[string][]corev1.Pod
+	var err error
+	return func(ctx context.Context, cluster kube.DBCluster) (bool, error) {
+		once.Do(func() {
+			var pods *corev1.PodList
+			pods, err = c.GetPods(ctx, "", selector)
+			if err != nil {
+				return
+			}
+			podsByCluster = groupPodsByLabel(pods.Items, instanceLabel)
+		})
+		if err != nil {
+			return false, err
+		}
+		return c.podsMatchCRImage(cluster, podsByCluster[cluster.Name()]), nil
+	}
+}
+
+// groupPodsByLabel groups pods by value of given label, pods without the label are skipped.
+func groupPodsByLabel(pods []corev1.Pod, label string) map[string][]corev1.Pod {
+	res := make(map[string][]corev1.Pod)
+	for _, pod := range pods {
+		value, ok := pod.Labels[label]
+		if !ok {
+			continue
+		}
+		res[value] = append(res[value], pod)
+	}
+	return res
+}
+
+// podsMatchCRImage returns true if database containers of all given pods run the image set in the custom resource.
+func (c *K8sClient) podsMatchCRImage(cluster kube.DBCluster, pods []corev1.Pod) bool {
+	if len(pods) == 0 {
+		// Avoid stating it versions don't match when there are no pods to check.
+		return true
+	}
+	images := make(map[string]struct{})
+	for _, p := range pods {
+		for _, containerName := range cluster.DatabaseContainerNames() {
 			var imageName string
 			for _, c := range p.Spec.Containers {
 				if c.Name == containerName {
@@ -1142,63 +3230,291 @@ func (c *K8sClient) crVersionMatchesPodsVersion(ctx context.Context, cluster kub
 				}
 			}
 			if imageName == "" {
-				c.l.Debugf("failed to check pods for container image: %v", err)
+				c.l.Debugf("failed to check pod %q for container %q image", p.Name, containerName)
 				continue
 			}
 			images[imageName] = struct{}{}
 		}
 	}
-	_, ok := images[cluster.CRImage]
-	return len(images) == 1 && ok, nil
+	_, ok := images[cluster.DatabaseImage()]
+	return len(images) == 1 && ok
+}
+
+// GetUpgradeProgress returns number of PXC or PSMDB cluster database pods already running the image
+// set in the custom resource and total number of database pods, it's meant to show progress of rolling upgrade.
+func (c *K8sClient) GetUpgradeProgress(ctx context.Context, name string) (done, total int32, err error) {
+	ctx = c.withRequestLogger(ctx, "GetUpgradeProgress", name)
+	pxcCluster, psmdbCluster, err := c.getDatabaseCluster(ctx, name)
+	if err != nil {
+		return 0, 0, err
+	}
+	var cluster kube.DBCluster
+	if pxcCluster != nil {
+		cluster = kube.NewDBClusterInfoFromPXC(pxcCluster)
+	} else {
+		cluster = kube.NewDBClusterInfoFromPSMDB(psmdbCluster)
+	}
+	pods, err := c.GetPods(ctx, "", strings.Join(cluster.DatabasePodLabels(), ","))
+	if err != nil {
+		return 0, 0, err
+	}
+	done, total = countPodsRunningCRImage(cluster, pods.Items)
+	return done, total, nil
+}
+
+// countPodsRunningCRImage returns number of pods whose database containers all run the image set in the custom resource
+// and total number of pods.
+func countPodsRunningCRImage(cluster kube.DBCluster, pods []corev1.Pod) (done, total int32) {
+	for _, p := range pods {
+		total++
+		var found, mismatched bool
+		for _, containerName := range cluster.DatabaseContainerNames() {
+			for _, container := range p.Spec.Containers {
+				if container.Name != containerName {
+					continue
+				}
+				found = true
+				if container.Image != cluster.DatabaseImage() {
+					mismatched = true
+				}
+			}
+		}
+		if found && !mismatched {
+			done++
+		}
+	}
+	return done, total
 }
 
 // getPSMDBClusters returns Percona Server for MongoDB clusters.
-func (c *K8sClient) getPSMDBClusters(ctx context.Context) ([]PSMDBCluster, error) {
+func (c *K8sClient) getPSMDBClusters(ctx context.Context, managedOnly bool) ([]PSMDBCluster, error) {
 	list, err := c.kube.ListPSMDBClusters(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if managedOnly {
+		items := list.Items[:0]
+		for _, cluster := range list.Items {
+			if isManagedByDBaaS(&cluster.ObjectMeta) {
+				items = append(items, cluster)
+			}
+		}
+		list.Items = items
+	}
 	res := make([]PSMDBCluster, len(list.Items))
+	crAndPodsMatchFunc := c.newBatchCRVersionMatcher("app.kubernetes.io/part-of=percona-server-mongodb", "app.kubernetes.io/instance")
+	g, gCtx := errgroup.WithContext(ctx)
+	g.SetLimit(listClustersConcurrency)
+	for i := range list.Items {
+		i := i
+		g.Go(func() error {
+			res[i] = c.toPSMDBCluster(gCtx, &list.Items[i], crAndPodsMatchFunc)
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// ClusterEventType is a type of cluster change sent by cluster watchers.
+type ClusterEventType string
+
+const (
+	// ClusterEventAdded is sent for clusters existing when watch starts and for created clusters.
+	ClusterEventAdded ClusterEventType = "added"
+	// ClusterEventModified is sent when cluster spec or status changes.
+	ClusterEventModified ClusterEventType = "modified"
+	// ClusterEventDeleted is sent when cluster custom resource is deleted, only cluster name is set.
+	ClusterEventDeleted ClusterEventType = "deleted"
+)
+
+// PXCClusterEvent represents a change of PXC cluster.
+type PXCClusterEvent struct {
+	Type    ClusterEventType
+	Cluster PXCCluster
+}
+
+// PSMDBClusterEvent represents a change of PSMDB cluster.
+type PSMDBClusterEvent struct {
+	Type    ClusterEventType
+	Cluster PSMDBCluster
+}
+
+// WatchPXCClusters sends ClusterEventAdded for all existing PXC clusters followed by changes of clusters.
+// Returned channel is closed and the watch is stopped when ctx is done or Kubernetes closes the watch,
+// callers should call WatchPXCClusters again in the latter case.
+func (c *K8sClient) WatchPXCClusters(ctx context.Context) (<-chan PXCClusterEvent, error) {
+	ctx = c.withRequestLogger(ctx, "WatchPXCClusters", "")
+	list, err := c.kube.ListPXCClusters(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "couldn't get Percona XtraDB clusters")
+	}
+	initial, err := meta.ExtractList(list)
 	if err != nil {
-		return res, err
+		return nil, errors.Wrap(err, "couldn't get Percona XtraDB clusters")
+	}
+	w, err := c.kube.WatchPXCClusters(ctx, list.ResourceVersion)
+	if err != nil {
+		return nil, errors.Wrap(err, "couldn't watch Percona XtraDB clusters")
 	}
-	for i, cluster := range list.Items {
 
-		val := PSMDBCluster{
-			Name:  cluster.Name,
-			Size:  cluster.Spec.Replsets[0].Size,
-			Pause: cluster.Spec.Pause,
-			Replicaset: &Replicaset{
-				DiskSize:         c.getPSMDBDiskSize(cluster.Spec.Replsets[0].VolumeSpec),
-				ComputeResources: c.getComputeResources(cluster.Spec.Replsets[0].Resources),
-			},
-			Exposed: cluster.Spec.Sharding.Mongos.Expose.ExposeType != corev1.ServiceTypeClusterIP,
-			Image:   cluster.Spec.Image,
+	return watchClusters(ctx, c, initial, w, func(eventType ClusterEventType, obj runtime.Object) (PXCClusterEvent, bool) {
+		cluster, ok := obj.(*pxcv1.PerconaXtraDBCluster)
+		if !ok {
+			return PXCClusterEvent{}, false
+		}
+		event := PXCClusterEvent{Type: eventType, Cluster: PXCCluster{Name: cluster.Name}}
+		if eventType != ClusterEventDeleted {
+			event.Cluster = c.toPXCCluster(ctx, cluster, c.crVersionMatchesPodsVersion)
+		}
+		return event, true
+	}), nil
+}
+
+// WatchPSMDBClusters is the same as WatchPXCClusters but for PSMDB clusters.
+func (c *K8sClient) WatchPSMDBClusters(ctx context.Context) (<-chan PSMDBClusterEvent, error) {
+	ctx = c.withRequestLogger(ctx, "WatchPSMDBClusters", "")
+	list, err := c.kube.ListPSMDBClusters(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "couldn't get PSMDB clusters")
+	}
+	initial, err := meta.ExtractList(list)
+	if err != nil {
+		return nil, errors.Wrap(err, "couldn't get PSMDB clusters")
+	}
+	w, err := c.kube.WatchPSMDBClusters(ctx, list.ResourceVersion)
+	if err != nil {
+		return nil, errors.Wrap(err, "couldn't watch PSMDB clusters")
+	}
+
+	return watchClusters(ctx, c, initial, w, func(eventType ClusterEventType, obj runtime.Object) (PSMDBClusterEvent, bool) {
+		cluster, ok := obj.(*psmdbv1.PerconaServerMongoDB)
+		if !ok {
+			return PSMDBClusterEvent{}, false
 		}
+		event := PSMDBClusterEvent{Type: eventType, Cluster: PSMDBCluster{Name: cluster.Name}}
+		if eventType != ClusterEventDeleted {
+			event.Cluster = c.toPSMDBCluster(ctx, cluster, c.crVersionMatchesPodsVersion)
+		}
+		return event, true
+	}), nil
+}
 
-		if len(cluster.Status.Conditions) > 0 {
-			message := cluster.Status.Message
-			conditions := cluster.Status.Conditions
-			if message == "" && len(conditions) > 0 {
-				message = conditions[len(conditions)-1].Message
+// watchClusters sends ClusterEventAdded for initial clusters followed by changes from w to the returned channel.
+// toEvent converts a cluster custom resource to an event, objects it rejects are skipped.
+// The channel is closed when ctx is done or the watch is closed.
+func watchClusters[E any](
+	ctx context.Context,
+	c *K8sClient,
+	initial []runtime.Object,
+	w watch.Interface,
+	toEvent func(ClusterEventType, runtime.Object) (E, bool),
+) <-chan E {
+	ch := make(chan E)
+	go func() {
+		defer close(ch)
+		send := func(eventType ClusterEventType, obj runtime.Object) bool {
+			event, ok := toEvent(eventType, obj)
+			if !ok {
+				return true
 			}
+			select {
+			case ch <- event:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+		for _, obj := range initial {
+			if !send(ClusterEventAdded, obj) {
+				w.Stop()
+				return
+			}
+		}
+		c.consumeWatch(ctx, w, send)
+	}()
+	return ch
+}
 
-			status := make([]appStatus, 0, len(cluster.Status.Replsets)+1)
-			for _, rs := range cluster.Status.Replsets {
-				status = append(status, appStatus{rs.Size, rs.Ready})
+// consumeWatch passes watch events to handle until ctx is done, handle returns false or the watch is closed.
+// The watch is stopped before returning.
+func (c *K8sClient) consumeWatch(ctx context.Context, w watch.Interface, handle func(ClusterEventType, runtime.Object) bool) {
+	defer w.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-w.ResultChan():
+			if !ok {
+				return
+			}
+			eventType, ok := clusterEventTypes[event.Type]
+			if !ok {
+				if event.Type == watch.Error {
+					c.requestLogger(ctx).Warnf("watch failed: %v", apiErrors.FromObject(event.Object))
+					return
+				}
+				continue
 			}
-			if val.Size != 1 {
-				status = append(status, appStatus{
-					size:  int32(cluster.Status.Mongos.Size),
-					ready: int32(cluster.Status.Mongos.Ready),
-				})
+			if !handle(eventType, event.Object) {
+				return
 			}
-			val.DetailedState = status
-			val.Message = message
+		}
+	}
+}
+
+var clusterEventTypes = map[watch.EventType]ClusterEventType{
+	watch.Added:    ClusterEventAdded,
+	watch.Modified: ClusterEventModified,
+	watch.Deleted:  ClusterEventDeleted,
+}
+
+// toPSMDBCluster converts PSMDB custom resource to PSMDBCluster.
+func (c *K8sClient) toPSMDBCluster(
+	ctx context.Context,
+	cluster *psmdbv1.PerconaServerMongoDB,
+	crAndPodsMatchFunc func(context.Context, kube.DBCluster) (bool, error),
+) PSMDBCluster {
+	val := PSMDBCluster{
+		Name:      cluster.Name,
+		UID:       string(cluster.UID),
+		Size:      cluster.Spec.Replsets[0].Size,
+		Pause:     cluster.Spec.Pause,
+		CreatedAt: cluster.CreationTimestamp.Time,
+		Replicaset: &Replicaset{
+			DiskSize:         c.getPSMDBDiskSize(cluster.Spec.Replsets[0].VolumeSpec),
+			ComputeResources: c.getComputeResources(cluster.Spec.Replsets[0].Resources),
+		},
+		Exposed: cluster.Spec.Sharding.Mongos.Expose.ExposeType != corev1.ServiceTypeClusterIP,
+		Image:   cluster.Spec.Image,
+	}
+
+	if len(cluster.Status.Conditions) > 0 {
+		message := cluster.Status.Message
+		conditions := cluster.Status.Conditions
+		if message == "" && len(conditions) > 0 {
+			message = conditions[len(conditions)-1].Message
 		}
 
-		clusterInfo := kube.NewDBClusterInfoFromPSMDB(&cluster)
-		val.State = c.getClusterState(ctx, clusterInfo, c.crVersionMatchesPodsVersion)
-		res[i] = val
+		status := make([]appStatus, 0, len(cluster.Status.Replsets)+1)
+		for _, rs := range cluster.Status.Replsets {
+			status = append(status, appStatus{rs.Size, rs.Ready})
+		}
+		if val.Size != 1 {
+			status = append(status, appStatus{
+				size:  int32(cluster.Status.Mongos.Size),
+				ready: int32(cluster.Status.Mongos.Ready),
+			})
+		}
+		val.DetailedState = status
+		val.Message = message
 	}
-	return res, nil
+
+	clusterInfo := kube.NewDBClusterInfoFromPSMDB(cluster)
+	val.State = c.getClusterState(ctx, clusterInfo, crAndPodsMatchFunc)
+	return val
 }
 
 // getDeletingPSMDBClusters returns Percona Server for MongoDB clusters which are not fully deleted yet.
@@ -1226,6 +3542,152 @@ func (c *K8sClient) getDeletingPSMDBClusters(ctx context.Context, clusters []PSM
 	return pxcClusters, nil
 }
 
+// ExportedPXCCluster contains parameters required to recreate Percona XtraDB cluster.
+type ExportedPXCCluster struct {
+	Params PXCParams `yaml:",inline"`
+	// SecretsName references the secret with cluster passwords, its values are never exported.
+	SecretsName string `yaml:"secretsName"`
+}
+
+// ExportedPSMDBCluster contains parameters required to recreate percona server for mongodb cluster.
+type ExportedPSMDBCluster struct {
+	Params PSMDBParams `yaml:",inline"`
+	// SecretsName references the secret with cluster passwords, its values are never exported.
+	SecretsName string `yaml:"secretsName"`
+}
+
+// ManagedClusters is a declarative snapshot of clusters managed by dbaas-controller.
+type ManagedClusters struct {
+	PXCClusters   []ExportedPXCCluster   `yaml:"pxcClusters"`
+	PSMDBClusters []ExportedPSMDBCluster `yaml:"psmdbClusters"`
+}
+
+// ExportManagedClusters returns YAML snapshot of parameters of all PXC and PSMDB clusters,
+// so they could be recreated elsewhere. Secrets are exported by reference only.
+func (c *K8sClient) ExportManagedClusters(ctx context.Context) ([]byte, error) {
+	ctx = c.withRequestLogger(ctx, "ExportManagedClusters", "")
+	pxcList, err := c.kube.ListPXCClusters(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "couldn't get Percona XtraDB clusters")
+	}
+	psmdbList, err := c.kube.ListPSMDBClusters(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "couldn't get PSMDB clusters")
+	}
+
+	res := ManagedClusters{
+		PXCClusters:   make([]ExportedPXCCluster, 0, len(pxcList.Items)),
+		PSMDBClusters: make([]ExportedPSMDBCluster, 0, len(psmdbList.Items)),
+	}
+	for i := range pxcList.Items {
+		res.PXCClusters = append(res.PXCClusters, c.exportPXCCluster(&pxcList.Items[i]))
+	}
+	for i := range psmdbList.Items {
+		res.PSMDBClusters = append(res.PSMDBClusters, c.exportPSMDBCluster(&psmdbList.Items[i]))
+	}
+
+	return yaml.Marshal(res)
+}
+
+// exportPXCCluster reconstructs parameters of the cluster from its custom resource.
+func (c *K8sClient) exportPXCCluster(cluster *pxcv1.PerconaXtraDBCluster) ExportedPXCCluster {
+	params := PXCParams{
+		Name:              cluster.Name,
+		Suspend:           cluster.Spec.Pause,
+		VersionServiceURL: cluster.Spec.UpgradeOptions.VersionServiceEndpoint,
+		AllowUnsafeConfig: pointer.ToBool(cluster.Spec.AllowUnsafeConfig),
+	}
+	if cluster.Spec.PXC != nil && cluster.Spec.PXC.PodSpec != nil {
+		params.Size = cluster.Spec.PXC.Size
+		params.AllowUnsafe = params.Size == 2
+		params.SchedulerName = cluster.Spec.PXC.SchedulerName
+		params.SecurityContext = exportSecurityContext(cluster.Spec.PXC.PodSecurityContext)
+		params.PriorityClassName = cluster.Spec.PXC.PriorityClassName
+		params.NodeSelector = cluster.Spec.PXC.NodeSelector
+		params.TerminationGracePeriodSeconds = cluster.Spec.PXC.TerminationGracePeriodSeconds
+		params.Expose = cluster.Spec.PXC.Expose.Enabled
+		params.PXC = &PXC{
+			Image:            cluster.Spec.PXC.Image,
+			DiskSize:         c.getPXCDiskSize(cluster.Spec.PXC.VolumeSpec),
+			ComputeResources: c.getComputeResources(cluster.Spec.PXC.Resources),
+		}
+	}
+	if cluster.Spec.ProxySQL != nil && cluster.Spec.ProxySQL.Enabled {
+		params.ProxySQL = &ProxySQL{
+			Image:            cluster.Spec.ProxySQL.Image,
+			DiskSize:         c.getPXCDiskSize(cluster.Spec.ProxySQL.VolumeSpec),
+			ComputeResources: c.getComputeResources(cluster.Spec.ProxySQL.Resources),
+		}
+		params.Expose = params.Expose || isExposed(cluster.Spec.ProxySQL.ServiceType, nil)
+	}
+	if cluster.Spec.HAProxy != nil && cluster.Spec.HAProxy.Enabled {
+		params.HAProxy = &HAProxy{
+			Image:            cluster.Spec.HAProxy.Image,
+			ComputeResources: c.getComputeResources(cluster.Spec.HAProxy.Resources),
+		}
+		params.Expose = params.Expose || isExposed(cluster.Spec.HAProxy.ServiceType, nil)
+	}
+	if cluster.Spec.PMM != nil && cluster.Spec.PMM.Enabled {
+		params.PMM = &PMM{
+			PublicAddress: cluster.Spec.PMM.ServerHost,
+			Login:         cluster.Spec.PMM.ServerUser,
+		}
+	}
+	return ExportedPXCCluster{
+		Params:      params,
+		SecretsName: cluster.Spec.SecretsName,
+	}
+}
+
+// exportPSMDBCluster reconstructs parameters of the cluster from its custom resource.
+func (c *K8sClient) exportPSMDBCluster(cluster *psmdbv1.PerconaServerMongoDB) ExportedPSMDBCluster {
+	params := PSMDBParams{
+		Name:              cluster.Name,
+		Image:             cluster.Spec.Image,
+		BackupImage:       cluster.Spec.Backup.Image,
+		VersionServiceURL: cluster.Spec.UpgradeOptions.VersionServiceEndpoint,
+		Suspend:           cluster.Spec.Pause,
+		SchedulerName:     cluster.Spec.SchedulerName,
+		MongoPort:         psmdbPort(cluster),
+		Expose:            cluster.Spec.Sharding.Mongos != nil && isExposed(cluster.Spec.Sharding.Mongos.Expose.ExposeType, nil),
+	}
+	if cluster.Spec.Sharding.Mongos != nil {
+		params.MongosServiceAnnotations = cluster.Spec.Sharding.Mongos.Expose.ServiceAnnotations
+	}
+	if cluster.Spec.ClusterServiceDNSSuffix != "" {
+		params.ClusterDomain = strings.TrimPrefix(cluster.Spec.ClusterServiceDNSSuffix, psmdbServiceDNSSuffixPrefix)
+	}
+	if len(cluster.Spec.Replsets) > 0 {
+		rs := cluster.Spec.Replsets[0]
+		params.Size = rs.Size
+		params.AllowUnsafe = params.Size == 2
+		params.Expose = params.Expose || rs.Expose.Enabled
+		params.SecurityContext = exportSecurityContext(rs.PodSecurityContext)
+		params.PriorityClassName = rs.PriorityClassName
+		params.NodeSelector = rs.NodeSelector
+		if rs.Expose.Enabled {
+			params.MongosServiceAnnotations = rs.Expose.ServiceAnnotations
+		}
+		params.Replicaset = &Replicaset{
+			DiskSize:         c.getPSMDBDiskSize(rs.VolumeSpec),
+			ComputeResources: c.getComputeResources(rs.Resources),
+		}
+	}
+	if cluster.Spec.PMM.Enabled {
+		params.PMM = &PMM{
+			PublicAddress: cluster.Spec.PMM.ServerHost,
+		}
+	}
+	var secretsName string
+	if cluster.Spec.Secrets != nil {
+		secretsName = cluster.Spec.Secrets.Users
+	}
+	return ExportedPSMDBCluster{
+		Params:      params,
+		SecretsName: secretsName,
+	}
+}
+
 func (c *K8sClient) getComputeResources(resources corev1.ResourceRequirements) *ComputeResources {
 	res := new(ComputeResources)
 	cpuLimit, ok := resources.Limits[corev1.ResourceCPU]
@@ -1317,6 +3779,7 @@ func (c *K8sClient) volumeSpec(diskSize string) *psmdbv1.VolumeSpec {
 
 // CheckOperators checks installed operator API version.
 func (c *K8sClient) CheckOperators(ctx context.Context) (*Operators, error) {
+	ctx = c.withRequestLogger(ctx, "CheckOperators", "")
 	apiVersions, err := c.kube.GetAPIVersions(ctx)
 	if err != nil {
 		return nil, errors.Wrap(err, "can't get api versions list")
@@ -1325,9 +3788,41 @@ func (c *K8sClient) CheckOperators(ctx context.Context) (*Operators, error) {
 	return &Operators{
 		PXCOperatorVersion:   c.getLatestOperatorAPIVersion(apiVersions, pxcAPINamespace),
 		PsmdbOperatorVersion: c.getLatestOperatorAPIVersion(apiVersions, psmdbAPINamespace),
+		apiVersions:          apiVersions,
 	}, nil
 }
 
+// validateCRAPIVersion checks that custom resource API version is served by installed operator.
+// Empty API version (template without apiVersion) is replaced by the latest installed one.
+func validateCRAPIVersion(typeMeta *metav1.TypeMeta, latest string, installedVersions []string, apiPrefix string) error {
+	if typeMeta.APIVersion == "" {
+		typeMeta.APIVersion = latest
+		return nil
+	}
+
+	var operatorVersions []string
+	for _, v := range installedVersions {
+		if !strings.HasPrefix(v, apiPrefix+"/") {
+			continue
+		}
+		if v == typeMeta.APIVersion {
+			return nil
+		}
+		operatorVersions = append(operatorVersions, v)
+	}
+	return errors.Wrapf(ErrAPIVersionNotInstalled, "%q, installed versions: [%s]", typeMeta.APIVersion, strings.Join(operatorVersions, ", "))
+}
+
+// CheckReadiness checks that Kubernetes API server is reachable.
+// Operators are not required: they are installed by PMM on demand.
+func (c *K8sClient) CheckReadiness(ctx context.Context) error {
+	ctx = c.withRequestLogger(ctx, "CheckReadiness", "")
+	if _, err := c.kube.GetServerVersion(ctx); err != nil {
+		return errors.Wrap(err, "Kubernetes API server is not reachable")
+	}
+	return nil
+}
+
 // getLatestOperatorAPIVersion returns latest installed operator API version.
 // It checks for all API versions supported by the operator and based on the latest API version in the list
 // figures out the version. Returns empty string if operator API is not installed.
@@ -1378,24 +3873,34 @@ func (c *K8sClient) GetPersistentVolumes(ctx context.Context) (*corev1.Persisten
 	return c.kube.GetPersistentVolumes(ctx)
 }
 
-// GetPods returns list of pods based on given filters. Filters are args to
-// kubectl command. For example "-lyour-label=value,next-label=value", "-ntest-namespace".
-func (c *K8sClient) GetPods(ctx context.Context, namespace string, filters ...string) (*corev1.PodList, error) {
-	podList, err := c.kube.GetPods(ctx, namespace, strings.Join(filters, ""))
+// GetPods returns list of pods in given namespace matching label selector,
+// for example "your-label=value,next-label=value". Empty namespace means all namespaces,
+// empty labelSelector means all pods.
+func (c *K8sClient) GetPods(ctx context.Context, namespace, labelSelector string) (*corev1.PodList, error) {
+	podList, err := c.kube.GetPods(ctx, namespace, labelSelector)
 	return podList, err
 }
 
 // GetLogs returns logs as slice of log lines - strings - for given pod's container.
+// If previous is true, logs of the previous container instance are returned, e.g. of the one which crashed.
+// tailLines limits number of returned lines, 0 means all lines; use DefaultLogTailLines if not sure.
 func (c *K8sClient) GetLogs(
 	ctx context.Context,
 	containerStatuses []corev1.ContainerStatus,
 	pod,
 	container string,
+	previous bool,
+	tailLines int64,
 ) ([]string, error) {
-	if common.IsContainerInState(containerStatuses, common.ContainerStateWaiting, container) {
+	ctx = c.withRequestLogger(ctx, "GetLogs", "")
+	if tailLines < 0 {
+		return nil, errors.Wrapf(ErrInvalidLogTailLines, "got %d", tailLines)
+	}
+	// Crash looping container is waiting for restart, but its previous instance has logs.
+	if !previous && common.IsContainerInState(containerStatuses, common.ContainerStateWaiting, container) {
 		return []string{}, nil
 	}
-	stdout, err := c.kube.GetLogs(ctx, pod, container)
+	stdout, err := c.kube.GetLogs(ctx, pod, container, tailLines, previous)
 	if err != nil {
 		return nil, errors.Wrap(err, "couldn't get logs")
 	}
@@ -1405,8 +3910,31 @@ func (c *K8sClient) GetLogs(
 	return strings.Split(string(stdout), "\n"), nil
 }
 
+// GetLogsSince returns log lines written after given time for given pod's container.
+func (c *K8sClient) GetLogsSince(
+	ctx context.Context,
+	containerStatuses []corev1.ContainerStatus,
+	pod,
+	container string,
+	since time.Time,
+) ([]string, error) {
+	ctx = c.withRequestLogger(ctx, "GetLogsSince", "")
+	if common.IsContainerInState(containerStatuses, common.ContainerStateWaiting, container) {
+		return []string{}, nil
+	}
+	stdout, err := c.kube.GetLogsSince(ctx, pod, container, since)
+	if err != nil {
+		return nil, errors.Wrap(err, "couldn't get logs")
+	}
+	if stdout == "" {
+		return []string{}, nil
+	}
+	return strings.Split(stdout, "\n"), nil
+}
+
 // GetEvents returns pod's events as a slice of strings.
 func (c *K8sClient) GetEvents(ctx context.Context, pod string) ([]string, error) {
+	ctx = c.withRequestLogger(ctx, "GetEvents", "")
 	stdout, err := c.kube.GetEvents(ctx, pod)
 	if err != nil {
 		return nil, errors.Wrap(err, "couldn't describe pod")
@@ -1416,18 +3944,28 @@ func (c *K8sClient) GetEvents(ctx context.Context, pod string) ([]string, error)
 }
 
 // getWorkerNodes returns list of cluster workers nodes.
-func (c *K8sClient) getWorkerNodes(ctx context.Context) ([]corev1.Node, error) {
+// If nodeSelector is not empty, only nodes with matching labels are returned.
+func (c *K8sClient) getWorkerNodes(ctx context.Context, nodeSelector map[string]string) ([]corev1.Node, error) {
 	nodes, err := c.kube.GetNodes(ctx)
 	if err != nil {
 		return nil, errors.Wrap(err, "could not get nodes of Kubernetes cluster")
 	}
+	return workerNodes(nodes.Items, nodeSelector), nil
+}
+
+// workerNodes filters out nodes which can't host database pods, including nodes which are not ready.
+func workerNodes(nodes []corev1.Node, nodeSelector map[string]string) []corev1.Node {
+	selector := labels.SelectorFromSet(nodeSelector)
 	forbidenTaints := map[string]corev1.TaintEffect{
 		"node.cloudprovider.kubernetes.io/uninitialized": corev1.TaintEffectNoSchedule,
 		"node.kubernetes.io/unschedulable":               corev1.TaintEffectNoSchedule,
 		"node-role.kubernetes.io/master":                 corev1.TaintEffectNoSchedule,
 	}
-	workers := make([]corev1.Node, 0, len(nodes.Items))
-	for _, node := range nodes.Items {
+	workers := make([]corev1.Node, 0, len(nodes))
+	for _, node := range nodes {
+		if !selector.Matches(labels.Set(node.Labels)) || !common.IsNodeInCondition(node, corev1.NodeReady) {
+			continue
+		}
 		if len(node.Spec.Taints) == 0 {
 			workers = append(workers, node)
 			continue
@@ -1439,14 +3977,23 @@ func (c *K8sClient) getWorkerNodes(ctx context.Context) ([]corev1.Node, error) {
 			}
 		}
 	}
-	return workers, nil
+	return workers
 }
 
 // GetAllClusterResources goes through all cluster nodes and sums their allocatable resources.
-func (c *K8sClient) GetAllClusterResources(ctx context.Context, clusterType KubernetesClusterType, volumes *corev1.PersistentVolumeList) (
+// Only ready nodes pods can be scheduled on are counted.
+// If nodeSelector is not empty, only nodes matching it are counted, it should be the same
+// node selector database pods are going to be scheduled with.
+func (c *K8sClient) GetAllClusterResources(
+	ctx context.Context,
+	clusterType KubernetesClusterType,
+	volumes *corev1.PersistentVolumeList,
+	nodeSelector map[string]string,
+) (
 	cpuMillis uint64, memoryBytes uint64, diskSizeBytes uint64, err error,
 ) {
-	nodes, err := c.getWorkerNodes(ctx)
+	ctx = c.withRequestLogger(ctx, "GetAllClusterResources", "")
+	nodes, err := c.getWorkerNodes(ctx, nodeSelector)
 	if err != nil {
 		return 0, 0, 0, errors.Wrap(err, "could not get a list of nodes")
 	}
@@ -1535,17 +4082,24 @@ func getResources(resources corev1.ResourceList) (cpuMillis uint64, memoryBytes
 }
 
 // GetConsumedCPUAndMemory returns consumed CPU and Memory in given namespace. If namespace
-// is empty, it tries to get them from all namespaces.
-func (c *K8sClient) GetConsumedCPUAndMemory(ctx context.Context, namespace string) (
+// is empty, it tries to get them from all namespaces. If labelSelector is not empty, only
+// matching pods are taken into account, e.g. DatabasePodsSelector for pods of database clusters.
+func (c *K8sClient) GetConsumedCPUAndMemory(ctx context.Context, namespace, labelSelector string) (
 	cpuMillis uint64, memoryBytes uint64, err error,
 ) {
+	ctx = c.withRequestLogger(ctx, "GetConsumedCPUAndMemory", "")
 	// Get CPU and Memory Requests of Pods' containers.
-	pods, err := c.GetPods(ctx, namespace)
+	pods, err := c.GetPods(ctx, namespace, labelSelector)
 	if err != nil {
 		return 0, 0, errors.Wrap(err, "failed to get consumed resources")
 	}
-	for _, ppod := range pods.Items {
-		if ppod.Status.Phase != corev1.PodRunning {
+	return podsRequests(pods.Items, corev1.PodRunning)
+}
+
+// podsRequests sums CPU and memory requests of pods in given phases.
+func podsRequests(pods []corev1.Pod, phases ...corev1.PodPhase) (cpuMillis uint64, memoryBytes uint64, err error) {
+	for _, ppod := range pods {
+		if !podInPhase(ppod, phases) {
 			continue
 		}
 		nonTerminatedInitContainers := make([]corev1.Container, 0, len(ppod.Spec.InitContainers))
@@ -1569,37 +4123,95 @@ func (c *K8sClient) GetConsumedCPUAndMemory(ctx context.Context, namespace strin
 	return cpuMillis, memoryBytes, nil
 }
 
+func podInPhase(pod corev1.Pod, phases []corev1.PodPhase) bool {
+	for _, phase := range phases {
+		if pod.Status.Phase == phase {
+			return true
+		}
+	}
+	return false
+}
+
+// GetAvailableClusterResources returns resources left for new clusters, that is allocatable resources
+// of all nodes minus requests of running and pending pods and consumed disk space.
+// Pending pods are taken into account as they are going to take their share once scheduled.
+// nodeSelector has the same meaning as for GetAllClusterResources, only pods scheduled
+// to the selected nodes and unscheduled pending pods are subtracted.
+func (c *K8sClient) GetAvailableClusterResources(
+	ctx context.Context,
+	clusterType KubernetesClusterType,
+	volumes *corev1.PersistentVolumeList,
+	nodeSelector map[string]string,
+) (
+	cpuMillis uint64, memoryBytes uint64, diskSizeBytes uint64, err error,
+) {
+	ctx = c.withRequestLogger(ctx, "GetAvailableClusterResources", "")
+	allCPUMillis, allMemoryBytes, allDiskSizeBytes, err := c.GetAllClusterResources(ctx, clusterType, volumes, nodeSelector)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	nodes, err := c.getWorkerNodes(ctx, nodeSelector)
+	if err != nil {
+		return 0, 0, 0, errors.Wrap(err, "could not get a list of nodes")
+	}
+	pods, err := c.GetPods(ctx, "", "")
+	if err != nil {
+		return 0, 0, 0, errors.Wrap(err, "failed to get consumed resources")
+	}
+	consumedCPUMillis, consumedMemoryBytes, err := podsRequests(podsOnNodes(pods.Items, nodes), corev1.PodRunning, corev1.PodPending)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	consumedDiskBytes, err := c.GetConsumedDiskBytes(ctx, clusterType, volumes)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	return subtractOrZero(allCPUMillis, consumedCPUMillis),
+		subtractOrZero(allMemoryBytes, consumedMemoryBytes),
+		subtractOrZero(allDiskSizeBytes, consumedDiskBytes),
+		nil
+}
+
+// podsOnNodes returns pods scheduled to given nodes and pending pods that are not scheduled yet.
+func podsOnNodes(pods []corev1.Pod, nodes []corev1.Node) []corev1.Pod {
+	names := make(map[string]struct{}, len(nodes))
+	for _, node := range nodes {
+		names[node.Name] = struct{}{}
+	}
+	res := make([]corev1.Pod, 0, len(pods))
+	for _, pod := range pods {
+		if pod.Spec.NodeName == "" {
+			if pod.Status.Phase == corev1.PodPending {
+				res = append(res, pod)
+			}
+			continue
+		}
+		if _, ok := names[pod.Spec.NodeName]; ok {
+			res = append(res, pod)
+		}
+	}
+	return res
+}
+
+// subtractOrZero returns a - b or zero if b is greater, e.g. when pods request more than nodes have.
+func subtractOrZero(a, b uint64) uint64 {
+	if b > a {
+		return 0
+	}
+	return a - b
+}
+
 // GetConsumedDiskBytes returns consumed bytes. The strategy differs based on k8s cluster type.
 func (c *K8sClient) GetConsumedDiskBytes(ctx context.Context, clusterType KubernetesClusterType, volumes *corev1.PersistentVolumeList) (consumedBytes uint64, err error) {
+	ctx = c.withRequestLogger(ctx, "GetConsumedDiskBytes", "")
 	//nolint: cyclop
 	switch clusterType {
 	case MinikubeClusterType:
-		nodes, err := c.getWorkerNodes(ctx)
-		if err != nil {
-			return 0, errors.Wrap(err, "can't compute consumed disk size: failed to get worker nodes")
-		}
-		clientConfig, err := clientcmd.NewClientConfigFromBytes([]byte(c.kubeconfig))
+		summaries, err := c.getNodeSummaries(ctx)
 		if err != nil {
-			return 0, errors.Wrap(err, "failed to build kubeconfig out of given path")
+			return 0, errors.Wrap(err, "can't compute consumed disk size")
 		}
-		config, err := clientConfig.ClientConfig()
-		if err != nil {
-			return 0, errors.Wrap(err, "failed to build kubeconfig out of given path")
-		}
-		clientset, err := kubernetes.NewForConfig(config)
-		if err != nil {
-			return 0, errors.Wrap(err, "failed to build client out of submited kubeconfig")
-		}
-		for _, node := range nodes {
-			var summary common.NodeSummary
-			request := clientset.CoreV1().RESTClient().Get().Resource("nodes").Name(node.Name).SubResource("proxy").Suffix("stats/summary")
-			responseRawArrayOfBytes, err := request.DoRaw(context.Background())
-			if err != nil {
-				return 0, errors.Wrap(err, "failed to get stats from node")
-			}
-			if err := json.Unmarshal(responseRawArrayOfBytes, &summary); err != nil {
-				return 0, errors.Wrap(err, "failed to unmarshal response from kubernetes API")
-			}
+		for _, summary := range summaries {
 			consumedBytes += summary.Node.FileSystem.UsedBytes
 		}
 		return consumedBytes, nil
@@ -1608,10 +4220,85 @@ func (c *K8sClient) GetConsumedDiskBytes(ctx context.Context, clusterType Kubern
 		if err != nil {
 			return 0, errors.Wrap(err, "failed to sum persistent volumes storage sizes")
 		}
-		return consumedBytes, nil
+		return consumedBytes, nil
+	}
+
+	return 0, nil
+}
+
+// getNodeSummaries returns stats summaries of all worker nodes.
+func (c *K8sClient) getNodeSummaries(ctx context.Context) ([]common.NodeSummary, error) {
+	nodes, err := c.getWorkerNodes(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get worker nodes")
+	}
+	summaries := make([]common.NodeSummary, 0, len(nodes))
+	for _, node := range nodes {
+		var summary common.NodeSummary
+		responseRawArrayOfBytes, err := c.kube.GetNodeStatsSummary(ctx, node.Name)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get stats from node")
+		}
+		if err := json.Unmarshal(responseRawArrayOfBytes, &summary); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal response from kubernetes API")
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries, nil
+}
+
+// VolumeUsage contains used and total space of a persistent volume.
+type VolumeUsage struct {
+	UsedBytes     uint64
+	CapacityBytes uint64
+}
+
+// GetClusterVolumeUsage returns usage of PXC or PSMDB cluster volumes keyed by persistent volume claim
+// namespace and name joined with slash, e.g. "default/datadir-test-pxc-0".
+// Used space is read from node stats on minikube; on other clusters only capacity of bound volumes is known.
+func (c *K8sClient) GetClusterVolumeUsage(ctx context.Context, clusterName string) (map[string]VolumeUsage, error) {
+	ctx = c.withRequestLogger(ctx, "GetClusterVolumeUsage", clusterName)
+	pvcs, err := c.kube.GetPersistentVolumeClaims(ctx, "app.kubernetes.io/instance="+clusterName)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get persistent volume claims")
+	}
+	var summaries []common.NodeSummary
+	if c.GetKubernetesClusterType(ctx) == MinikubeClusterType {
+		summaries, err = c.getNodeSummaries(ctx)
+		if err != nil {
+			return nil, errors.Wrap(err, "can't compute volume usage")
+		}
+	}
+	return volumeUsage(pvcs.Items, summaries), nil
+}
+
+// volumeUsage returns usage of given persistent volume claims.
+// Capacity is taken from claim status and replaced with volume stats from node summaries if they are present.
+// Claims with the same name in other namespaces are not matched.
+func volumeUsage(pvcs []corev1.PersistentVolumeClaim, summaries []common.NodeSummary) map[string]VolumeUsage {
+	res := make(map[string]VolumeUsage, len(pvcs))
+	for _, pvc := range pvcs {
+		var usage VolumeUsage
+		if capacity, ok := pvc.Status.Capacity[corev1.ResourceStorage]; ok {
+			usage.CapacityBytes = uint64(capacity.Value())
+		}
+		res[pvc.Namespace+"/"+pvc.Name] = usage
 	}
-
-	return 0, nil
+	for _, summary := range summaries {
+		for _, pod := range summary.Pods {
+			for _, volume := range pod.Volumes {
+				if volume.PVCRef == nil {
+					continue
+				}
+				key := volume.PVCRef.Namespace + "/" + volume.PVCRef.Name
+				if _, ok := res[key]; !ok {
+					continue
+				}
+				res[key] = VolumeUsage{UsedBytes: volume.UsedBytes, CapacityBytes: volume.CapacityBytes}
+			}
+		}
+	}
+	return res
 }
 
 func (c *K8sClient) getAPIVersionForPSMDBOperator(version string) string {
@@ -1627,14 +4314,14 @@ func (c *K8sClient) fetchOperatorManifest(ctx context.Context, manifestURL strin
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to fetch operator manifests")
 	}
-	resp, err := c.client.Do(req)
+	resp, err := c.manifestClient.Do(req)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to fetch operator manifests")
 	}
 	defer func() {
 		err := resp.Body.Close()
 		if err != nil {
-			c.l.Errorf("failed to close response's body: %v", err)
+			c.requestLogger(ctx).Errorf("failed to close response's body: %v", err)
 		}
 	}()
 	if resp.StatusCode != http.StatusOK {
@@ -1643,19 +4330,223 @@ func (c *K8sClient) fetchOperatorManifest(ctx context.Context, manifestURL strin
 	return io.ReadAll(resp.Body)
 }
 
+// ListAvailableOperatorVersions returns operator versions which can be installed from repository
+// the manifests URL template points to, sorted from the newest one.
+// Only templates of manifests hosted on GitHub are supported, versions are discovered from repository tags.
+func (c *K8sClient) ListAvailableOperatorVersions(ctx context.Context, manifestsURLTemplate string) ([]string, error) {
+	ctx = c.withRequestLogger(ctx, "ListAvailableOperatorVersions", "")
+	tagsURL, tagPrefix, err := githubTagsURL(manifestsURLTemplate)
+	if err != nil {
+		return nil, err
+	}
+
+	var tags []string
+	for page := 1; page <= maxGitHubTagsPages; page++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s?per_page=100&page=%d", tagsURL, page), nil)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to list operator versions")
+		}
+		pageTags, err := c.fetchGitHubTags(req)
+		if err != nil {
+			return nil, err
+		}
+		if len(pageTags) == 0 {
+			break
+		}
+		tags = append(tags, pageTags...)
+	}
+	return versionsFromTags(tags, tagPrefix), nil
+}
+
+func (c *K8sClient) fetchGitHubTags(req *http.Request) ([]string, error) {
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list operator versions")
+	}
+	defer func() {
+		err := resp.Body.Close()
+		if err != nil {
+			c.requestLogger(req.Context()).Errorf("failed to close response's body: %v", err)
+		}
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("failed to list operator versions, http request ended with status %q", resp.Status)
+	}
+	var tags []struct {
+		Name string `json:"name"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tags); err != nil {
+		return nil, errors.Wrap(err, "failed to decode operator versions")
+	}
+	res := make([]string, len(tags))
+	for i, tag := range tags {
+		res[i] = tag.Name
+	}
+	return res, nil
+}
+
+// githubTagsURL returns GitHub API URL of repository tags for given manifests URL template,
+// for example https://raw.githubusercontent.com/percona/percona-xtradb-cluster-operator/v%s/deploy/%s,
+// and prefix of tags preceding version.
+func githubTagsURL(manifestsURLTemplate string) (string, string, error) {
+	// Verbs are not valid URL escapes, replace them before parsing.
+	const placeholder = "__placeholder__"
+	u, err := url.Parse(strings.ReplaceAll(manifestsURLTemplate, "%s", placeholder))
+	if err != nil {
+		return "", "", errors.Wrap(err, "failed to parse manifests URL template")
+	}
+	parts := strings.Split(strings.TrimPrefix(u.Path, "/"), "/")
+	if u.Host != "raw.githubusercontent.com" || len(parts) < 3 || !strings.HasSuffix(parts[2], placeholder) {
+		return "", "", errors.Errorf("listing versions is not supported for manifests URL template %q", manifestsURLTemplate)
+	}
+	return fmt.Sprintf("https://api.github.com/repos/%s/%s/tags", parts[0], parts[1]), strings.TrimSuffix(parts[2], placeholder), nil
+}
+
+// versionsFromTags returns released versions from tags with given prefix sorted from the newest one.
+func versionsFromTags(tags []string, prefix string) []string {
+	versions := make([]*goversion.Version, 0, len(tags))
+	for _, tag := range tags {
+		if !strings.HasPrefix(tag, prefix) {
+			continue
+		}
+		v, err := goversion.NewVersion(strings.TrimPrefix(tag, prefix))
+		if err != nil || v.Prerelease() != "" {
+			continue
+		}
+		versions = append(versions, v)
+	}
+	sort.Sort(sort.Reverse(goversion.Collection(versions)))
+	res := make([]string, len(versions))
+	for i, v := range versions {
+		res[i] = v.Original()
+	}
+	return res
+}
+
 // ApplyOperator applies bundle.yaml which installs CRDs, RBAC and operator's deployment.
 func (c *K8sClient) ApplyOperator(ctx context.Context, version string, manifestsURLTemplate string) error {
+	_, err := c.applyOperatorBundle(c.withRequestLogger(ctx, "ApplyOperator", ""), version, manifestsURLTemplate)
+	return err
+}
+
+// ValidateOperator checks that operator bundle can be applied using server-side dry run, the cluster is not changed.
+// It returns errors of all objects which would fail to be applied.
+func (c *K8sClient) ValidateOperator(ctx context.Context, version string, manifestsURLTemplate string) error {
+	ctx = c.withRequestLogger(ctx, "ValidateOperator", "")
+	bundleURL := fmt.Sprintf(manifestsURLTemplate, version, "bundle.yaml")
+	bundle, err := c.fetchOperatorManifest(ctx, bundleURL)
+	if err != nil {
+		return errors.Wrap(err, "failed to validate operator")
+	}
+	return c.kube.ApplyFileDryRun(ctx, bundle)
+}
+
+// ApplyOperatorAndWait installs the operator and waits until all its deployments are available
+// or timeout is reached.
+func (c *K8sClient) ApplyOperatorAndWait(ctx context.Context, version, manifestsURLTemplate string, timeout time.Duration) error {
+	ctx = c.withRequestLogger(ctx, "ApplyOperatorAndWait", "")
+	bundle, err := c.applyOperatorBundle(ctx, version, manifestsURLTemplate)
+	if err != nil {
+		return err
+	}
+	deployments, err := c.kube.GetDeploymentNames(bundle)
+	if err != nil {
+		return errors.Wrap(err, "failed to get operator deployments")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	for _, name := range deployments {
+		if err := c.kube.WaitForDeploymentAvailable(ctx, name); err != nil {
+			return errors.Wrapf(err, "operator deployment %q is not available", name)
+		}
+	}
+	return nil
+}
+
+// DeleteOperator uninstalls operator installed by ApplyOperator: it deletes operator deployment and RBAC.
+// CRDs are deleted only if deleteCRDs is set and there are no database clusters managed by them.
+func (c *K8sClient) DeleteOperator(ctx context.Context, deploymentName, manifestsURLTemplate, version string, deleteCRDs bool) error {
+	ctx = c.withRequestLogger(ctx, "DeleteOperator", "")
+	var crdManifest []byte
+	if deleteCRDs {
+		var err error
+		crdManifest, err = c.fetchOperatorManifest(ctx, fmt.Sprintf(manifestsURLTemplate, version, "crd.yaml"))
+		if err != nil {
+			return errors.Wrap(err, "failed to delete operator")
+		}
+		// Check before anything is deleted, so operator is not left half-removed.
+		if err = c.checkNoManagedClusters(ctx, crdManifest); err != nil {
+			return err
+		}
+	}
+
+	deployment := &appsv1.Deployment{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "apps/v1",
+			Kind:       "Deployment",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: deploymentName,
+		},
+	}
+	if err := c.kube.Delete(ctx, deployment); err != nil && !apiErrors.IsNotFound(err) {
+		return errors.Wrap(err, "failed to delete operator deployment")
+	}
+
+	rbac, err := c.fetchOperatorManifest(ctx, fmt.Sprintf(manifestsURLTemplate, version, "rbac.yaml"))
+	if err != nil {
+		return errors.Wrap(err, "failed to delete operator")
+	}
+	if err = c.kube.DeleteFile(ctx, rbac, kube.StopOnError); err != nil && !apiErrors.IsNotFound(err) {
+		return errors.Wrap(err, "failed to delete operator RBAC")
+	}
+
+	if crdManifest == nil {
+		return nil
+	}
+	// Missing CRDs are skipped, other failures don't stop deletion of the rest.
+	if err = c.kube.DeleteFile(ctx, crdManifest, kube.ContinueOnError); err != nil {
+		return errors.Wrap(err, "failed to delete operator CRDs")
+	}
+	return nil
+}
+
+// checkNoManagedClusters returns ErrClustersExist if there are custom resources of CRDs defined in given manifest
+// in any namespace. CRDs are cluster-scoped, so deleting them would delete clusters, backups and restores everywhere.
+func (c *K8sClient) checkNoManagedClusters(ctx context.Context, crdManifest []byte) error {
+	counts, err := c.kube.CountCustomResources(ctx, crdManifest)
+	if err != nil {
+		return errors.Wrap(err, "failed to count custom resources of operator CRDs")
+	}
+	crds := make([]string, 0, len(counts))
+	for crd, count := range counts {
+		if count > 0 {
+			crds = append(crds, fmt.Sprintf("%d of %s", count, crd))
+		}
+	}
+	if len(crds) > 0 {
+		sort.Strings(crds)
+		return errors.Wrapf(ErrClustersExist, "%s", strings.Join(crds, ", "))
+	}
+	return nil
+}
+
+// applyOperatorBundle fetches and applies operator bundle, it returns applied bundle.
+// Objects created by the failed attempt are deleted, so operator is not left half-installed.
+func (c *K8sClient) applyOperatorBundle(ctx context.Context, version string, manifestsURLTemplate string) ([]byte, error) {
 	bundleURL := fmt.Sprintf(manifestsURLTemplate, version, "bundle.yaml")
 	bundle, err := c.fetchOperatorManifest(ctx, bundleURL)
 	if err != nil {
-		return errors.Wrap(err, "failed to install operator")
+		return nil, errors.Wrap(err, "failed to install operator")
 	}
-	return c.kube.ApplyFile(ctx, bundle)
+	return bundle, c.kube.ApplyFile(ctx, bundle, kube.RollbackOnError)
 }
 
 // PatchAllPSMDBClusters replaces images versions and CrVersion after update of the operator to match version
 // of the installed operator.
 func (c *K8sClient) PatchAllPSMDBClusters(ctx context.Context, oldVersion, newVersion string) error {
+	ctx = c.withRequestLogger(ctx, "PatchAllPSMDBClusters", "")
 	list, err := c.kube.ListPSMDBClusters(ctx)
 	if err != nil {
 		return errors.Wrap(err, "couldn't get percona server MongoDB clusters")
@@ -1686,6 +4577,7 @@ func (c *K8sClient) PatchAllPSMDBClusters(ctx context.Context, oldVersion, newVe
 // PatchAllPXCClusters replaces the image versions and crVersion after update of the operator to match version
 // of the installed operator.
 func (c *K8sClient) PatchAllPXCClusters(ctx context.Context, oldVersion, newVersion string) error {
+	ctx = c.withRequestLogger(ctx, "PatchAllPXCClusters", "")
 	list, err := c.kube.ListPXCClusters(ctx)
 	if err != nil {
 		return errors.Wrap(err, "couldn't get percona XtraDB clusters")
@@ -1726,8 +4618,117 @@ func (c *K8sClient) PatchAllPXCClusters(ctx context.Context, oldVersion, newVers
 	return nil
 }
 
+// validateServiceAccount returns ErrNotFound if non-empty service account doesn't exist.
+func (c *K8sClient) validateServiceAccount(ctx context.Context, name string) error {
+	if name == "" {
+		return nil
+	}
+	_, err := c.kube.GetServiceAccount(ctx, name)
+	if err != nil {
+		if apiErrors.IsNotFound(err) {
+			return errors.Wrapf(ErrNotFound, "service account %q", name)
+		}
+		return errors.Wrap(err, "failed to get service account")
+	}
+	return nil
+}
+
+// validatePriorityClass returns ErrNotFound if non-empty priority class doesn't exist.
+func (c *K8sClient) validatePriorityClass(ctx context.Context, name string) error {
+	if name == "" {
+		return nil
+	}
+	_, err := c.kube.GetPriorityClass(ctx, name)
+	if err != nil {
+		if apiErrors.IsNotFound(err) {
+			return errors.Wrapf(ErrNotFound, "priority class %q", name)
+		}
+		return errors.Wrap(err, "failed to get priority class")
+	}
+	return nil
+}
+
+// setPXCPriorityClassName sets priority class of PXC and proxy pods.
+func setPXCPriorityClassName(spec *pxcv1.PerconaXtraDBCluster, name string) {
+	spec.Spec.PXC.PriorityClassName = name
+	if spec.Spec.ProxySQL != nil {
+		spec.Spec.ProxySQL.PriorityClassName = name
+	}
+	if spec.Spec.HAProxy != nil {
+		spec.Spec.HAProxy.PriorityClassName = name
+	}
+}
+
+// setPSMDBPriorityClassName sets priority class of replica set, config server and mongos pods.
+func setPSMDBPriorityClassName(spec *psmdbv1.PerconaServerMongoDB, name string) {
+	for _, rs := range spec.Spec.Replsets {
+		rs.PriorityClassName = name
+	}
+	if spec.Spec.Sharding.ConfigsvrReplSet != nil {
+		spec.Spec.Sharding.ConfigsvrReplSet.PriorityClassName = name
+	}
+	if spec.Spec.Sharding.Mongos != nil {
+		spec.Spec.Sharding.Mongos.PriorityClassName = name
+	}
+}
+
+// setPXCNodeSelector sets node selector of PXC and proxy pods.
+func setPXCNodeSelector(spec *pxcv1.PerconaXtraDBCluster, nodeSelector map[string]string) {
+	spec.Spec.PXC.NodeSelector = nodeSelector
+	if spec.Spec.ProxySQL != nil {
+		spec.Spec.ProxySQL.NodeSelector = nodeSelector
+	}
+	if spec.Spec.HAProxy != nil {
+		spec.Spec.HAProxy.NodeSelector = nodeSelector
+	}
+}
+
+// setPSMDBNodeSelector sets node selector of replica set, config server and mongos pods.
+func setPSMDBNodeSelector(spec *psmdbv1.PerconaServerMongoDB, nodeSelector map[string]string) {
+	for _, rs := range spec.Spec.Replsets {
+		rs.NodeSelector = nodeSelector
+	}
+	if spec.Spec.Sharding.ConfigsvrReplSet != nil {
+		spec.Spec.Sharding.ConfigsvrReplSet.NodeSelector = nodeSelector
+	}
+	if spec.Spec.Sharding.Mongos != nil {
+		spec.Spec.Sharding.Mongos.NodeSelector = nodeSelector
+	}
+}
+
+// checkOperatorNotUpgrading returns ErrOperatorUpgrading if rollout of operator deployment is in progress,
+// e.g. after UpdateOperator, so custom resources are not created with API version which is going away.
+// Operators installed under a different deployment name are not checked.
+func (c *K8sClient) checkOperatorNotUpgrading(ctx context.Context, deploymentName string) error {
+	deployment, err := c.kube.GetDeployment(ctx, deploymentName)
+	if err != nil {
+		if apiErrors.IsNotFound(err) {
+			return nil
+		}
+		return errors.Wrap(err, "failed to get operator deployment")
+	}
+	if isRolloutInProgress(deployment) {
+		return errors.Wrapf(ErrOperatorUpgrading, "deployment %q", deploymentName)
+	}
+	return nil
+}
+
+// isRolloutInProgress returns true if deployment controller hasn't processed the latest spec yet
+// or not all replicas are updated to it.
+func isRolloutInProgress(deployment *appsv1.Deployment) bool {
+	if deployment.Generation != deployment.Status.ObservedGeneration {
+		return true
+	}
+	replicas := int32(1)
+	if deployment.Spec.Replicas != nil {
+		replicas = *deployment.Spec.Replicas
+	}
+	return deployment.Status.UpdatedReplicas < replicas || deployment.Status.Replicas > deployment.Status.UpdatedReplicas
+}
+
 // UpdateOperator updates images inside operator deployment and also applies new CRDs and RBAC.
 func (c *K8sClient) UpdateOperator(ctx context.Context, version, deploymentName, manifestsURLTemplate string) error {
+	ctx = c.withRequestLogger(ctx, "UpdateOperator", "")
 	files := []string{"crd.yaml", "rbac.yaml"}
 	for _, file := range files {
 		manifestURL := fmt.Sprintf(manifestsURLTemplate, version, file)
@@ -1735,7 +4736,7 @@ func (c *K8sClient) UpdateOperator(ctx context.Context, version, deploymentName,
 		if err != nil {
 			return errors.Wrap(err, "failed to update operator")
 		}
-		err = c.kube.ApplyFile(ctx, manifest)
+		err = c.kube.ApplyFile(ctx, manifest, kube.StopOnError)
 		if err != nil {
 			return errors.Wrap(err, "failed to update operator")
 		}
@@ -1762,7 +4763,52 @@ func (c *K8sClient) UpdateOperator(ctx context.Context, version, deploymentName,
 	return c.kube.PatchDeployment(ctx, deploymentName, deployment)
 }
 
-func (c *K8sClient) CreateVMOperator(ctx context.Context, params *PMM) error {
+// VMAgentParams contains optional parameters of VMAgent shipping Kubernetes cluster metrics to PMM.
+type VMAgentParams struct {
+	// ReplicaCount is a number of VMAgent replicas, 1 is used if zero.
+	ReplicaCount int
+	// ScrapeInterval is a default scrape interval like 30s, VictoriaMetrics default is used if empty.
+	ScrapeInterval string
+	// RemoteWrite are additional targets metrics are sent to besides PMM server.
+	RemoteWrite []RemoteWriteTarget
+}
+
+// RemoteWriteTarget is a TSDB endpoint accepting Prometheus remote write protocol.
+type RemoteWriteTarget struct {
+	URL string
+	// BasicAuthSecret is a name of secret with username and password keys, basic auth is not used if empty.
+	BasicAuthSecret string
+	// TLS configures verification of target certificate, system CAs are used if nil.
+	TLS *RemoteWriteTLS
+}
+
+// RemoteWriteTLS contains TLS parameters of remote write target.
+type RemoteWriteTLS struct {
+	// CASecret is a name of secret with CA certificate under ca.crt key.
+	CASecret           string
+	ServerName         string
+	InsecureSkipVerify bool
+}
+
+// CreateVMOperator installs VictoriaMetrics agent sending metrics to PMM server. agent may be nil.
+func (c *K8sClient) CreateVMOperator(ctx context.Context, params *PMM, agent *VMAgentParams) error {
+	ctx = c.withRequestLogger(ctx, "CreateVMOperator", "")
+	if agent != nil {
+		if agent.ReplicaCount < 0 {
+			return errors.Errorf("invalid VMAgent replica count %d", agent.ReplicaCount)
+		}
+		if agent.ScrapeInterval != "" {
+			if _, err := time.ParseDuration(agent.ScrapeInterval); err != nil {
+				return errors.Wrapf(err, "invalid VMAgent scrape interval %q", agent.ScrapeInterval)
+			}
+		}
+		for _, target := range agent.RemoteWrite {
+			if u, err := url.Parse(target.URL); err != nil || u.Scheme == "" || u.Host == "" {
+				return errors.Errorf("invalid remote write URL %q", target.URL)
+			}
+		}
+	}
+
 	files := []string{
 		"deploy/victoriametrics/crs/vmnodescrape.yaml",
 		"deploy/victoriametrics/crs/vmpodscrape.yaml",
@@ -1778,7 +4824,8 @@ func (c *K8sClient) CreateVMOperator(ctx context.Context, params *PMM) error {
 		if err != nil {
 			return err
 		}
-		err = c.kube.ApplyFile(ctx, file)
+		// Apply all objects, so failed ones are reported together and can be fixed at once.
+		err = c.kube.ApplyFile(ctx, file, kube.ContinueOnError)
 		if err != nil {
 			return errors.Wrapf(err, "cannot apply file: %q", path)
 		}
@@ -1798,12 +4845,13 @@ func (c *K8sClient) CreateVMOperator(ctx context.Context, params *PMM) error {
 		return err
 	}
 
-	vmagent := vmAgentSpec(params, secretName)
+	vmagent := vmAgentSpec(params, agent, secretName)
 	return c.kube.Apply(ctx, vmagent)
 }
 
 // RemoveVMOperator deletes the VM Operator installed when the cluster was registered.
 func (c *K8sClient) RemoveVMOperator(ctx context.Context) error {
+	ctx = c.withRequestLogger(ctx, "RemoveVMOperator", "")
 	files := []string{
 		"deploy/victoriametrics/kube-state-metrics.yaml",
 		"deploy/victoriametrics/kube-state-metrics/cluster-role-binding.yaml",
@@ -1820,9 +4868,9 @@ func (c *K8sClient) RemoveVMOperator(ctx context.Context) error {
 		if err != nil {
 			return err
 		}
-		err = c.kube.DeleteFile(ctx, file)
+		err = c.kube.DeleteFile(ctx, file, kube.ContinueOnError)
 		if err != nil {
-			return errors.Wrapf(err, "cannot apply file: %q", path)
+			return errors.Wrapf(err, "cannot delete file: %q", path)
 		}
 	}
 
@@ -1831,6 +4879,7 @@ func (c *K8sClient) RemoveVMOperator(ctx context.Context) error {
 
 // Create the resource from the specs.
 func (c *K8sClient) Create(ctx context.Context, resource interface{}) error {
+	ctx = c.withRequestLogger(ctx, "Create", "")
 	var err error
 
 	switch res := resource.(type) {
@@ -1846,26 +4895,46 @@ func (c *K8sClient) Create(ctx context.Context, resource interface{}) error {
 	return nil
 }
 
-// WaitForCondition waits until the condition is met for the specified resource.
+// WaitForCondition waits until the condition is met for all objects of the specified resource,
+// which is either a path to manifest file or manifest contents.
+// Waiting is stopped when context is done or after waitForConditionTimeout.
 func (c *K8sClient) WaitForCondition(ctx context.Context, condition string, resource interface{}) error {
-	var err error
-
-	condition = "--for=condition=" + condition
-
+	ctx = c.withRequestLogger(ctx, "WaitForCondition", "")
+	var manifest []byte
 	switch res := resource.(type) {
 	case string:
-		_, err = c.kubeCtl.Run(ctx, []string{"wait", "-f", res}, nil)
+		b, err := os.ReadFile(res)
+		if err != nil {
+			return errors.Wrap(err, "failed to read manifest")
+		}
+		manifest = b
 	case []byte:
-		_, err = c.kubeCtl.Run(ctx, []string{"wait", "-f", "-"}, res)
+		manifest = res
+	default:
+		return errors.Errorf("unsupported resource type %T", resource)
 	}
-	if err != nil {
+
+	ctx, cancel := context.WithTimeout(ctx, waitForConditionTimeout)
+	defer cancel()
+	if err := c.kube.WaitForCondition(ctx, condition, manifest); err != nil {
 		return errors.Wrapf(err, "error while waiting for condition %q", condition)
 	}
-
 	return nil
 }
 
-func vmAgentSpec(params *PMM, secretName string) *monitoring.VMAgent {
+func vmAgentSpec(params *PMM, agent *VMAgentParams, secretName string) *monitoring.VMAgent {
+	replicaCount := 1
+	var scrapeInterval string
+	var remoteWrite []monitoring.VMAgentRemoteWriteSpec
+	if agent != nil {
+		if agent.ReplicaCount > 0 {
+			replicaCount = agent.ReplicaCount
+		}
+		scrapeInterval = agent.ScrapeInterval
+		for _, target := range agent.RemoteWrite {
+			remoteWrite = append(remoteWrite, remoteWriteSpec(target))
+		}
+	}
 	return &monitoring.VMAgent{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "VMAgent",
@@ -1883,22 +4952,24 @@ func vmAgentSpec(params *PMM, secretName string) *monitoring.VMAgent {
 			ProbeNamespaceSelector:         new(metav1.LabelSelector),
 			StaticScrapeSelector:           new(metav1.LabelSelector),
 			StaticScrapeNamespaceSelector:  new(metav1.LabelSelector),
-			ReplicaCount:                   1,
+			ReplicaCount:                   replicaCount,
+			ScrapeInterval:                 scrapeInterval,
 			SelectAllByDefault:             true,
 			Resources: &corev1.ResourceRequirements{
 				Requests: corev1.ResourceList{
-					corev1.ResourceCPU:    resource.MustParse("250m"),
-					corev1.ResourceMemory: resource.MustParse("350Mi"),
+					corev1.ResourceCPU:    resource.MustParse(convertors.MilliCPUToStr(vmAgentCPURequestM)),
+					corev1.ResourceMemory: resource.MustParse(convertors.BytesToStr(vmAgentMemoryRequestBytes)),
 				},
 				Limits: corev1.ResourceList{
-					corev1.ResourceCPU:    resource.MustParse("500m"),
-					corev1.ResourceMemory: resource.MustParse("850Mi"),
+					corev1.ResourceCPU:    resource.MustParse(convertors.MilliCPUToStr(vmAgentCPULimitM)),
+					corev1.ResourceMemory: resource.MustParse(convertors.BytesToStr(vmAgentMemoryLimitBytes)),
 				},
 			},
 			ExtraArgs: map[string]string{
 				"memory.allowedPercent": "40",
 			},
-			RemoteWrite: []monitoring.VMAgentRemoteWriteSpec{
+			// PMM server is always the first target.
+			RemoteWrite: append([]monitoring.VMAgentRemoteWriteSpec{
 				{
 					URL: fmt.Sprintf("%s/victoriametrics/api/v1/write", params.PublicAddress),
 					TLSConfig: &monitoring.TLSConfig{
@@ -1919,9 +4990,287 @@ func vmAgentSpec(params *PMM, secretName string) *monitoring.VMAgent {
 						},
 					},
 				},
+			}, remoteWrite...),
+		},
+	}
+}
+
+// remoteWriteSpec converts additional remote write target to VMAgent spec.
+func remoteWriteSpec(target RemoteWriteTarget) monitoring.VMAgentRemoteWriteSpec {
+	spec := monitoring.VMAgentRemoteWriteSpec{URL: target.URL}
+	if target.BasicAuthSecret != "" {
+		spec.BasicAuth = &monitoring.BasicAuth{
+			Username: corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: target.BasicAuthSecret},
+				Key:                  "username",
 			},
+			Password: corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: target.BasicAuthSecret},
+				Key:                  "password",
+			},
+		}
+	}
+	if target.TLS != nil {
+		spec.TLSConfig = &monitoring.TLSConfig{
+			ServerName:         target.TLS.ServerName,
+			InsecureSkipVerify: target.TLS.InsecureSkipVerify,
+		}
+		if target.TLS.CASecret != "" {
+			spec.TLSConfig.CA = &monitoring.SecretOrConfigMap{
+				Secret: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: target.TLS.CASecret},
+					Key:                  "ca.crt",
+				},
+			}
+		}
+	}
+	return spec
+}
+
+// pmmClientResources returns resources of pmm-client sidecar containers.
+// Given resources are used both as requests and limits, default requests are used for missing values.
+func pmmClientResources(res *ComputeResources) corev1.ResourceRequirements {
+	req := corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceMemory: resource.MustParse(convertors.BytesToStr(pmmClientMemoryRequestBytes)),
+			corev1.ResourceCPU:    resource.MustParse(convertors.MilliCPUToStr(pmmClientCPURequestM)),
 		},
 	}
+	if res == nil {
+		return req
+	}
+	req.Limits = corev1.ResourceList{}
+	if res.CPUM != "" {
+		req.Requests[corev1.ResourceCPU] = resource.MustParse(res.CPUM)
+		req.Limits[corev1.ResourceCPU] = resource.MustParse(res.CPUM)
+	}
+	if res.MemoryBytes != "" {
+		req.Requests[corev1.ResourceMemory] = resource.MustParse(res.MemoryBytes)
+		req.Limits[corev1.ResourceMemory] = resource.MustParse(res.MemoryBytes)
+	}
+	return req
+}
+
+// validatePMMParams checks that pmm-client resources are valid quantities.
+func validatePMMParams(pmm *PMM) error {
+	if pmm == nil {
+		return nil
+	}
+	return validateComputeResources(pmm.Resources, "pmm-client")
+}
+
+// validateComputeResources checks that resources of given container can be parsed as quantities,
+// so setComputeResources doesn't panic. Nil resources are valid.
+func validateComputeResources(res *ComputeResources, container string) error {
+	if res == nil {
+		return nil
+	}
+	if res.CPUM != "" {
+		if _, err := resource.ParseQuantity(res.CPUM); err != nil {
+			return errors.Wrapf(ErrInvalidComputeResources, "%s CPU %q: %v", container, res.CPUM, err)
+		}
+	}
+	if res.MemoryBytes != "" {
+		if _, err := resource.ParseQuantity(res.MemoryBytes); err != nil {
+			return errors.Wrapf(ErrInvalidComputeResources, "%s memory %q: %v", container, res.MemoryBytes, err)
+		}
+	}
+	return nil
+}
+
+// pxcPMMSecrets returns PMM credentials keyed the way given PXC operator version reads them from cluster secret.
+// PMM login is passed in custom resource spec. API key is read from pmmserverkey since operator 1.12,
+// older versions only support password stored in pmmserver.
+func pxcPMMSecrets(operatorVersion string, pmm *PMM) (map[string][]byte, error) {
+	if pmm.APIKey == "" {
+		return map[string][]byte{"pmmserver": []byte(pmm.Password)}, nil
+	}
+	if !operatorVersionAtLeast(operatorVersion, v112) {
+		return nil, errors.Wrapf(ErrPMMAPIKeyNotSupported, "PXC operator %q", operatorVersion)
+	}
+	return map[string][]byte{"pmmserverkey": []byte(pmm.APIKey)}, nil
+}
+
+// psmdbPMMSecrets returns PMM credentials keyed the way given PSMDB operator version reads them from cluster secret.
+// API key is read from PMM_SERVER_API_KEY since operator 1.13, login and password are supported by all versions.
+func psmdbPMMSecrets(operatorVersion string, pmm *PMM) (map[string][]byte, error) {
+	if pmm.APIKey == "" {
+		return map[string][]byte{
+			"PMM_SERVER_USER":     []byte(pmm.Login),
+			"PMM_SERVER_PASSWORD": []byte(pmm.Password),
+		}, nil
+	}
+	if !operatorVersionAtLeast(operatorVersion, v113) {
+		return nil, errors.Wrapf(ErrPMMAPIKeyNotSupported, "PSMDB operator %q", operatorVersion)
+	}
+	return map[string][]byte{"PMM_SERVER_API_KEY": []byte(pmm.APIKey)}, nil
+}
+
+// operatorVersionAtLeast returns true if operator version is not older than min. Invalid versions are considered old.
+func operatorVersionAtLeast(operatorVersion string, min *goversion.Version) bool {
+	v, err := goversion.NewVersion(operatorVersion)
+	if err != nil {
+		return false
+	}
+	return v.GreaterThanOrEqual(min)
+}
+
+// validateBackupCompression returns ErrInvalidBackupCompression if compression type isn't supported by backup tasks.
+func validateBackupCompression(compression string) error {
+	switch pbm.CompressionType(compression) {
+	case "", pbm.CompressionTypeGZIP, pbm.CompressionTypePGZIP, pbm.CompressionTypeSNAPPY, pbm.CompressionTypeLZ4, pbm.CompressionTypeS2:
+		return nil
+	default:
+		return errors.Wrapf(ErrInvalidBackupCompression, "%q, use %s, %s, %s, %s or %s", compression,
+			pbm.CompressionTypeGZIP, pbm.CompressionTypePGZIP, pbm.CompressionTypeSNAPPY, pbm.CompressionTypeLZ4, pbm.CompressionTypeS2)
+	}
+}
+
+// setPSMDBBackupCompression sets compression type of backup tasks which don't have one.
+// ErrInvalidBackupCompression is returned if compression is set, but there are no backup tasks to apply it to.
+func setPSMDBBackupCompression(spec *psmdbv1.PerconaServerMongoDB, compression string) error {
+	if compression == "" {
+		return nil
+	}
+	if len(spec.Spec.Backup.Tasks) == 0 {
+		return errors.Wrap(ErrInvalidBackupCompression, "cluster has no backup tasks, define them in template or overrides")
+	}
+	for i := range spec.Spec.Backup.Tasks {
+		if spec.Spec.Backup.Tasks[i].CompressionType == "" {
+			spec.Spec.Backup.Tasks[i].CompressionType = pbm.CompressionType(compression)
+		}
+	}
+	return nil
+}
+
+// profilingModeOff disables MongoDB operation profiling, the operator doesn't define a constant for it.
+const profilingModeOff psmdbv1.OperationProfilingMode = "off"
+
+// validateProfilingParams checks that operation profiling parameters are supported by MongoDB.
+func validateProfilingParams(params *PSMDBParams) error {
+	switch psmdbv1.OperationProfilingMode(params.ProfilingMode) {
+	case "", psmdbv1.OperationProfilingModeAll, psmdbv1.OperationProfilingModeSlowOp, profilingModeOff:
+	default:
+		return errors.Wrapf(ErrInvalidProfilingMode, "%q, use %s, %s or %s",
+			params.ProfilingMode, psmdbv1.OperationProfilingModeAll, psmdbv1.OperationProfilingModeSlowOp, profilingModeOff)
+	}
+	if params.SlowOpThresholdMs < 0 || params.RateLimit < 0 {
+		return errors.Wrap(ErrInvalidProfilingParams, "slow operation threshold and rate limit can't be negative")
+	}
+	return nil
+}
+
+// operationProfiling returns operation profiling spec, slow operations over 100ms are profiled by default.
+func operationProfiling(params *PSMDBParams) *psmdbv1.MongodSpecOperationProfiling {
+	res := &psmdbv1.MongodSpecOperationProfiling{
+		Mode:              psmdbv1.OperationProfilingModeSlowOp,
+		SlowOpThresholdMs: 100,
+		RateLimit:         100,
+	}
+	if params.ProfilingMode != "" {
+		res.Mode = psmdbv1.OperationProfilingMode(params.ProfilingMode)
+	}
+	if params.SlowOpThresholdMs != 0 {
+		res.SlowOpThresholdMs = params.SlowOpThresholdMs
+	}
+	if params.RateLimit != 0 {
+		res.RateLimit = params.RateLimit
+	}
+	return res
+}
+
+// profilingConfiguration returns replica set configuration block with operation profiling settings.
+// Only mode is set for default settings.
+func profilingConfiguration(profiling *psmdbv1.MongodSpecOperationProfiling) psmdbv1.MongoConfiguration {
+	conf := "      operationProfiling:\n" +
+		"        mode: " + string(profiling.Mode) + "\n"
+	if profiling.SlowOpThresholdMs != 100 {
+		conf += fmt.Sprintf("        slowOpThresholdMs: %d\n", profiling.SlowOpThresholdMs)
+	}
+	if profiling.RateLimit != 100 {
+		conf += fmt.Sprintf("        rateLimit: %d\n", profiling.RateLimit)
+	}
+	return psmdbv1.MongoConfiguration(conf)
+}
+
+// mergeProfilingConfiguration returns replica set configuration, e.g. from template, with operation profiling
+// settings set in its operationProfiling section. Other mongod options and profiling options are kept.
+func mergeProfilingConfiguration(conf psmdbv1.MongoConfiguration, profiling *psmdbv1.MongodSpecOperationProfiling) (psmdbv1.MongoConfiguration, error) {
+	if strings.TrimSpace(string(conf)) == "" {
+		return profilingConfiguration(profiling), nil
+	}
+	var doc yaml.MapSlice
+	if err := yaml.Unmarshal([]byte(conf), &doc); err != nil {
+		return "", errors.Wrap(err, "cannot parse replica set configuration")
+	}
+	var section yaml.MapSlice
+	i := 0
+	for ; i < len(doc); i++ {
+		if doc[i].Key == "operationProfiling" {
+			section, _ = doc[i].Value.(yaml.MapSlice)
+			break
+		}
+	}
+	section = setMapSliceItem(section, "mode", string(profiling.Mode))
+	section = setMapSliceItem(section, "slowOpThresholdMs", profiling.SlowOpThresholdMs)
+	section = setMapSliceItem(section, "rateLimit", profiling.RateLimit)
+	if i == len(doc) {
+		doc = append(doc, yaml.MapItem{Key: "operationProfiling"})
+	}
+	doc[i].Value = section
+
+	res, err := yaml.Marshal(doc)
+	if err != nil {
+		return "", errors.Wrap(err, "cannot marshal replica set configuration")
+	}
+	return psmdbv1.MongoConfiguration(res), nil
+}
+
+// setMapSliceItem sets value of the key keeping order of existing keys, new keys are appended.
+func setMapSliceItem(items yaml.MapSlice, key string, value interface{}) yaml.MapSlice {
+	for i := range items {
+		if items[i].Key == key {
+			items[i].Value = value
+			return items
+		}
+	}
+	return append(items, yaml.MapItem{Key: key, Value: value})
+}
+
+// validateEncryptionParams checks that encryption cipher mode is supported by the operator.
+func validateEncryptionParams(enc *EncryptionParams) error {
+	if enc == nil {
+		return nil
+	}
+	switch psmdbv1.MongodChiperMode(enc.CipherMode) {
+	case psmdbv1.MongodChiperModeUnset, psmdbv1.MongodChiperModeCBC, psmdbv1.MongodChiperModeGCM:
+		return nil
+	default:
+		return errors.Wrapf(ErrInvalidCipherMode, "%q, use %s or %s", enc.CipherMode, psmdbv1.MongodChiperModeCBC, psmdbv1.MongodChiperModeGCM)
+	}
+}
+
+// setEncryption sets data-at-rest encryption fields of security spec, encryption is enabled with AES256-CBC by default.
+func setEncryption(security *psmdbv1.MongodSpecSecurity, params *PSMDBParams) *psmdbv1.MongodSpecSecurity {
+	enabled, cipherMode := true, psmdbv1.MongodChiperModeCBC
+	if params.Encryption != nil {
+		enabled = params.Encryption.Enabled
+		if params.Encryption.CipherMode != "" {
+			cipherMode = psmdbv1.MongodChiperMode(params.Encryption.CipherMode)
+		}
+	}
+	security.EnableEncryption = pointer.ToBool(enabled)
+	if !enabled {
+		security.EncryptionKeySecret = ""
+		security.EncryptionCipherMode = psmdbv1.MongodChiperModeUnset
+		return security
+	}
+	security.EncryptionKeySecret = params.EncryptionKeySecret
+	if security.EncryptionKeySecret == "" {
+		security.EncryptionKeySecret = fmt.Sprintf(psmdbEncryptionKeySecretTmpl, params.Name)
+	}
+	security.EncryptionCipherMode = cipherMode
+	return security
 }
 
 func (c *K8sClient) getPSMDBSpec(params *PSMDBParams, extra extraCRParams) *psmdbv1.PerconaServerMongoDB {
@@ -1939,13 +5288,14 @@ func (c *K8sClient) getPSMDBSpec(params *PSMDBParams, extra extraCRParams) *psmd
 			UpdateStrategy: updateStrategyRollingUpdate,
 			CRVersion:      extra.operators.PsmdbOperatorVersion,
 			Image:          extra.psmdbImage,
+			SchedulerName:  params.SchedulerName,
 			Secrets: &psmdbv1.SecretsSpec{
 				Users: extra.secretName,
 			},
 			Sharding: psmdbv1.Sharding{
 				Enabled: true,
 				ConfigsvrReplSet: &psmdbv1.ReplsetSpec{
-					Size:       params.Size,
+					Size:       psmdbConfigServerSize(params),
 					VolumeSpec: c.volumeSpec(params.Replicaset.DiskSize),
 					Arbiter: psmdbv1.Arbiter{
 						Enabled: false,
@@ -1972,7 +5322,7 @@ func (c *K8sClient) getPSMDBSpec(params *PSMDBParams, extra extraCRParams) *psmd
 				// Note: in case to support single node environments
 				// we need to expose primary mongodb node
 				{
-					Name: "rs0",
+					Name: psmdbDefaultReplsetName,
 					Size: params.Size,
 					Arbiter: psmdbv1.Arbiter{
 						Enabled: false,
@@ -1989,8 +5339,7 @@ func (c *K8sClient) getPSMDBSpec(params *PSMDBParams, extra extraCRParams) *psmd
 						Affinity:  extra.affinity,
 						Resources: c.setComputeResources(params.Replicaset.ComputeResources),
 					},
-					Configuration: psmdbv1.MongoConfiguration("      operationProfiling:\n" +
-						"        mode: " + string(psmdbv1.OperationProfilingModeSlowOp) + "\n"),
+					Configuration: profilingConfiguration(operationProfiling(params)),
 				},
 			},
 
@@ -1999,19 +5348,10 @@ func (c *K8sClient) getPSMDBSpec(params *PSMDBParams, extra extraCRParams) *psmd
 			},
 			Mongod: &psmdbv1.MongodSpec{
 				Net: &psmdbv1.MongodSpecNet{
-					Port: 27017,
-				},
-				OperationProfiling: &psmdbv1.MongodSpecOperationProfiling{
-					Mode:              psmdbv1.OperationProfilingModeSlowOp,
-					SlowOpThresholdMs: 100,
-					RateLimit:         100,
-				},
-				Security: &psmdbv1.MongodSpecSecurity{
-					RedactClientLogData:  false,
-					EnableEncryption:     pointer.ToBool(true),
-					EncryptionKeySecret:  fmt.Sprintf("%s-mongodb-encryption-key", params.Name),
-					EncryptionCipherMode: psmdbv1.MongodChiperModeCBC,
+					Port: psmdbDefaultPort,
 				},
+				OperationProfiling: operationProfiling(params),
+				Security:           setEncryption(&psmdbv1.MongodSpecSecurity{RedactClientLogData: false}, params),
 				SetParameter: &psmdbv1.MongodSpecSetParameter{
 					TTLMonitorSleepSecs: 60,
 				},
@@ -2044,6 +5384,15 @@ func (c *K8sClient) getPSMDBSpec(params *PSMDBParams, extra extraCRParams) *psmd
 		},
 	}
 
+	if params.MongoPort != 0 {
+		res.Spec.Mongod.Net.Port = params.MongoPort
+		res.Spec.Sharding.Mongos.Port = params.MongoPort
+	}
+
+	if params.ReplsetName != "" {
+		res.Spec.Replsets[0].Name = params.ReplsetName
+	}
+
 	if params.Replicaset != nil {
 		res.Spec.Replsets[0].Resources = c.setComputeResources(params.Replicaset.ComputeResources)
 		res.Spec.Sharding.Mongos.Resources = c.setComputeResources(params.Replicaset.ComputeResources)
@@ -2065,43 +5414,275 @@ func (c *K8sClient) getPSMDBSpec(params *PSMDBParams, extra extraCRParams) *psmd
 			Enabled:    true,
 			ServerHost: params.PMM.PublicAddress,
 			Image:      pmmClientImage,
-			Resources: corev1.ResourceRequirements{
-				Requests: corev1.ResourceList{
-					corev1.ResourceMemory: resource.MustParse("300M"),
-					corev1.ResourceCPU:    resource.MustParse("500m"),
-				},
-			},
+			Resources:  pmmClientResources(params.PMM.Resources),
 		}
 	}
 
 	return res
 }
 
-func (c *K8sClient) createPSMDBSpec(operator *goversion.Version, params *PSMDBParams, extra *extraCRParams) (*psmdbv1.PerconaServerMongoDB, error) {
+func (c *K8sClient) createPSMDBSpec(ctx context.Context, operator *goversion.Version, params *PSMDBParams, extra *extraCRParams) (*psmdbv1.PerconaServerMongoDB, error) {
 	spec := new(psmdbv1.PerconaServerMongoDB)
-	bytes, err := ioutil.ReadFile(psmdbCRFile)
+	var bytes []byte
+	var err error
+	if params.TemplateName != "" {
+		bytes, err = c.getNamedTemplate(ctx, params.TemplateName, kube.PSMDBKind)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		bytes, err = c.readCRTemplate(psmdbCRFile)
+	}
 	if err == nil {
 		err = c.unmarshalTemplate(bytes, spec)
 		if err != nil {
 			return nil, err
 		}
+		if spec.Spec.Secrets == nil {
+			spec.Spec.Secrets = new(psmdbv1.SecretsSpec)
+		}
 		if spec.Spec.Secrets.Users != "" {
 			extra.secretName = spec.Spec.Secrets.Users
 		}
 		if spec.Spec.Secrets.Users == "" {
 			spec.Spec.Secrets.Users = extra.secretName
 		}
-		return c.overridePSMDBSpec(spec, params, *extra), nil
+		spec, err = c.overridePSMDBSpec(spec, params, *extra)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		spec = c.getPSMDBSpec(params, *extra)
+	}
+	if params.BackupResources != nil {
+		spec.Spec.Backup.Resources = c.setComputeResources(params.BackupResources)
+	}
+	if params.BackupServiceAccount != "" {
+		spec.Spec.Backup.ServiceAccountName = params.BackupServiceAccount
+	}
+	if params.SecurityContext != nil {
+		spec.Spec.Replsets[0].PodSecurityContext = podSecurityContext(spec.Spec.Replsets[0].PodSecurityContext, params.SecurityContext)
+		if spec.Spec.Sharding.ConfigsvrReplSet != nil {
+			cfg := spec.Spec.Sharding.ConfigsvrReplSet
+			cfg.PodSecurityContext = podSecurityContext(cfg.PodSecurityContext, params.SecurityContext)
+		}
+	}
+	if params.PriorityClassName != "" {
+		setPSMDBPriorityClassName(spec, params.PriorityClassName)
+	}
+	if len(params.NodeSelector) != 0 {
+		setPSMDBNodeSelector(spec, params.NodeSelector)
+	}
+	if params.ClusterDomain != "" {
+		spec.Spec.ClusterServiceDNSSuffix = psmdbServiceDNSSuffixPrefix + params.ClusterDomain
+	}
+	setPSMDBServiceAnnotations(spec, params.MongosServiceAnnotations)
+	setPSMDBServiceAnnotations(spec, extra.expose.ServiceAnnotations)
+	if len(params.ExternalMembers) > 0 {
+		spec.Spec.Replsets[0].ExternalNodes = psmdbExternalNodes(params.ExternalMembers)
+	}
+	setManagedByLabel(&spec.ObjectMeta)
+	if err := applyOverrides(spec, params.Overrides); err != nil {
+		return nil, err
+	}
+	// Tasks come from template or overrides, compression set there explicitly is kept.
+	if err := setPSMDBBackupCompression(spec, params.BackupCompression); err != nil {
+		return nil, err
+	}
+	return spec, nil
+}
+
+// validateClusterDomain returns ErrInvalidClusterDomain if non-empty cluster domain isn't a valid DNS subdomain.
+func validateClusterDomain(domain string) error {
+	if domain == "" {
+		return nil
+	}
+	if errs := validation.IsDNS1123Subdomain(domain); len(errs) != 0 {
+		return errors.Wrapf(ErrInvalidClusterDomain, "%q: %s", domain, strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// validateReplsetName returns ErrInvalidReplsetName if non-empty replica set name isn't a valid DNS label.
+// Operator uses it in names of StatefulSets, Services and Pods.
+func validateReplsetName(name string) error {
+	if name == "" {
+		return nil
+	}
+	if errs := validation.IsDNS1123Label(name); len(errs) != 0 {
+		return errors.Wrapf(ErrInvalidReplsetName, "%q: %s", name, strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// validateTerminationGracePeriod returns ErrInvalidTerminationGracePeriod if grace period is set and negative.
+func validateTerminationGracePeriod(seconds *int64) error {
+	if seconds != nil && *seconds < 0 {
+		return errors.Wrapf(ErrInvalidTerminationGracePeriod, "%d seconds", *seconds)
+	}
+	return nil
+}
+
+// validateSecurityContext checks that user and group IDs are valid Linux IDs Kubernetes accepts.
+func validateSecurityContext(sc *SecurityContext) error {
+	if sc == nil {
+		return nil
+	}
+	for name, id := range map[string]*int64{"runAsUser": sc.RunAsUser, "runAsGroup": sc.RunAsGroup, "fsGroup": sc.FSGroup} {
+		if id != nil && (*id < 0 || *id > math.MaxInt32) {
+			return errors.Wrapf(ErrInvalidSecurityContext, "%s %d is out of [0, %d] range", name, *id, math.MaxInt32)
+		}
+	}
+	return nil
+}
+
+// podSecurityContext returns a copy of pod security context with IDs set in sc.
+func podSecurityContext(psc *corev1.PodSecurityContext, sc *SecurityContext) *corev1.PodSecurityContext {
+	res := new(corev1.PodSecurityContext)
+	if psc != nil {
+		res = psc.DeepCopy()
+	}
+	if sc.RunAsUser != nil {
+		res.RunAsUser = pointer.ToInt64(*sc.RunAsUser)
+	}
+	if sc.RunAsGroup != nil {
+		res.RunAsGroup = pointer.ToInt64(*sc.RunAsGroup)
+	}
+	if sc.FSGroup != nil {
+		res.FSGroup = pointer.ToInt64(*sc.FSGroup)
+	}
+	return res
+}
+
+// exportSecurityContext returns IDs set in pod security context, or nil if none are set.
+func exportSecurityContext(psc *corev1.PodSecurityContext) *SecurityContext {
+	if psc == nil || (psc.RunAsUser == nil && psc.RunAsGroup == nil && psc.FSGroup == nil) {
+		return nil
+	}
+	return &SecurityContext{RunAsUser: psc.RunAsUser, RunAsGroup: psc.RunAsGroup, FSGroup: psc.FSGroup}
+}
+
+// validatePSMDBExternalMembers checks external members of the first replica set of the spec.
+// Clusters without external members are not checked, their size is validated by validateClusterSize.
+func validatePSMDBExternalMembers(spec *psmdbv1.PerconaServerMongoDB, members []ReplsetMember) error {
+	if len(members) == 0 || len(spec.Spec.Replsets) == 0 {
+		return nil
+	}
+	rs := spec.Spec.Replsets[0]
+	votes := rs.Size
+	if rs.Arbiter.Enabled {
+		votes += rs.Arbiter.Size
+	}
+	return validateReplsetMembers(votes, members)
+}
+
+// validateReplsetMembers checks priorities and votes of external members
+// and that replica set of given number of managed voting members, including arbiters,
+// and external members has an odd number of votes.
+func validateReplsetMembers(votingMembers int32, members []ReplsetMember) error {
+	votes := int(votingMembers)
+	for _, m := range members {
+		if m.Host == "" {
+			return errors.Wrap(ErrInvalidReplsetMembers, "member host is empty")
+		}
+		if m.Priority < 0 || m.Priority > 1000 {
+			return errors.Wrapf(ErrInvalidReplsetMembers, "member %q priority %d is out of [0, 1000] range", m.Host, m.Priority)
+		}
+		if m.Votes != 0 && m.Votes != 1 {
+			return errors.Wrapf(ErrInvalidReplsetMembers, "member %q votes must be 0 or 1, got %d", m.Host, m.Votes)
+		}
+		if m.Votes == 0 && m.Priority != 0 {
+			return errors.Wrapf(ErrInvalidReplsetMembers, "non-voting member %q must have zero priority", m.Host)
+		}
+		votes += m.Votes
+	}
+	if votes%2 == 0 {
+		return errors.Wrapf(ErrInvalidReplsetMembers, "replica set has %d votes, number of votes must be odd", votes)
+	}
+	return nil
+}
+
+func psmdbExternalNodes(members []ReplsetMember) []*psmdbv1.ExternalNode {
+	nodes := make([]*psmdbv1.ExternalNode, 0, len(members))
+	for _, m := range members {
+		port := m.Port
+		if port == 0 {
+			port = psmdbDefaultPort
+		}
+		nodes = append(nodes, &psmdbv1.ExternalNode{Host: m.Host, Port: port, Priority: m.Priority, Votes: m.Votes})
+	}
+	return nodes
+}
+
+// setPSMDBServiceAnnotations sets annotations on services clients connect to:
+// mongos service and replica set service if it's exposed instead of mongos in single node clusters.
+func setPSMDBServiceAnnotations(spec *psmdbv1.PerconaServerMongoDB, annotations map[string]string) {
+	if len(annotations) == 0 {
+		return
+	}
+	if spec.Spec.Sharding.Mongos != nil {
+		spec.Spec.Sharding.Mongos.Expose.ServiceAnnotations = mergeAnnotations(spec.Spec.Sharding.Mongos.Expose.ServiceAnnotations, annotations)
+	}
+	if len(spec.Spec.Replsets) > 0 && spec.Spec.Replsets[0].Expose.Enabled {
+		spec.Spec.Replsets[0].Expose.ServiceAnnotations = mergeAnnotations(spec.Spec.Replsets[0].Expose.ServiceAnnotations, annotations)
+	}
+}
+
+// setPXCServiceAnnotations sets annotations on proxy service clients connect to.
+func setPXCServiceAnnotations(spec *pxcv1.PerconaXtraDBCluster, annotations map[string]string) {
+	if len(annotations) == 0 {
+		return
+	}
+	if spec.Spec.ProxySQL != nil {
+		spec.Spec.ProxySQL.ServiceAnnotations = mergeAnnotations(spec.Spec.ProxySQL.ServiceAnnotations, annotations)
 	}
-	return c.getPSMDBSpec(params, *extra), nil
+	if spec.Spec.HAProxy != nil {
+		spec.Spec.HAProxy.ServiceAnnotations = mergeAnnotations(spec.Spec.HAProxy.ServiceAnnotations, annotations)
+	}
+}
+
+// mergeAnnotations returns a copy of dst with annotations from src added, src takes precedence.
+func mergeAnnotations(dst, src map[string]string) map[string]string {
+	res := make(map[string]string, len(dst)+len(src))
+	for k, v := range dst {
+		res[k] = v
+	}
+	for k, v := range src {
+		res[k] = v
+	}
+	return res
+}
+
+// internalExposeAnnotations returns service annotations requesting internal load balancer for given cluster type.
+func internalExposeAnnotations(clusterType KubernetesClusterType) (map[string]string, error) {
+	annotations, ok := internalLoadBalancerAnnotations[clusterType]
+	if !ok {
+		return nil, ErrInternalExposeNotSupported
+	}
+	return annotations, nil
 }
 
-func (c *K8sClient) createPXCSpecFromParams(params *PXCParams, secretName *string, pxcOperatorVersion, storageName string, serviceType corev1.ServiceType) (*pxcv1.PerconaXtraDBCluster, error) {
+func (c *K8sClient) createPXCSpecFromParams(
+	ctx context.Context,
+	params *PXCParams,
+	secretName *string,
+	pxcOperatorVersion,
+	storageName string,
+	serviceType corev1.ServiceType,
+) (*pxcv1.PerconaXtraDBCluster, error) {
 	spec := new(pxcv1.PerconaXtraDBCluster)
 
-	bytes, err := ioutil.ReadFile(pxcCRFile)
+	var bytes []byte
+	var err error
+	if params.TemplateName != "" {
+		bytes, err = c.getNamedTemplate(ctx, params.TemplateName, kube.PXCKind)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		bytes, err = c.readCRTemplate(pxcCRFile)
+	}
 	if err == nil {
-		c.l.Debug("found pxc cr template")
 		err = c.unmarshalTemplate(bytes, spec)
 		if err != nil {
 			return nil, err
@@ -2112,21 +5693,287 @@ func (c *K8sClient) createPXCSpecFromParams(params *PXCParams, secretName *strin
 		if spec.Spec.SecretsName == "" {
 			spec.Spec.SecretsName = *secretName
 		}
-		return c.overridePXCSpec(spec, params, storageName, pxcOperatorVersion), nil
+		spec = c.overridePXCSpec(spec, params, storageName, pxcOperatorVersion)
+	} else {
+		spec = c.getDefaultPXCSpec(params, *secretName, pxcOperatorVersion, storageName, serviceType)
+	}
+	if err := c.setPXCBackupStorages(spec, params); err != nil {
+		return nil, err
+	}
+	if params.BackupServiceAccount != "" && spec.Spec.Backup != nil {
+		spec.Spec.Backup.ServiceAccountName = params.BackupServiceAccount
+	}
+	if params.AllowUnsafeConfig != nil {
+		spec.Spec.AllowUnsafeConfig = *params.AllowUnsafeConfig
+	}
+	if params.SecurityContext != nil {
+		spec.Spec.PXC.PodSecurityContext = podSecurityContext(spec.Spec.PXC.PodSecurityContext, params.SecurityContext)
+	}
+	if params.PriorityClassName != "" {
+		setPXCPriorityClassName(spec, params.PriorityClassName)
+	}
+	if params.TerminationGracePeriodSeconds != nil {
+		spec.Spec.PXC.TerminationGracePeriodSeconds = pointer.ToInt64(*params.TerminationGracePeriodSeconds)
+	}
+	if len(params.NodeSelector) != 0 {
+		setPXCNodeSelector(spec, params.NodeSelector)
+	}
+	// Backup jobs take resources from the storage they write to.
+	if params.BackupResources != nil && spec.Spec.Backup != nil {
+		for _, storage := range spec.Spec.Backup.Storages {
+			storage.Resources = c.setComputeResources(params.BackupResources)
+		}
+	}
+	setManagedByLabel(&spec.ObjectMeta)
+	if err := applyOverrides(spec, params.Overrides); err != nil {
+		return nil, err
+	}
+	return spec, nil
+}
+
+// setPXCBackupStorages replaces backup storages and schedules of the spec with ones from params.
+func (c *K8sClient) setPXCBackupStorages(spec *pxcv1.PerconaXtraDBCluster, params *PXCParams) error {
+	if len(params.BackupStorages) == 0 && len(params.BackupSchedules) == 0 {
+		return nil
+	}
+	if spec.Spec.Backup == nil {
+		spec.Spec.Backup = new(pxcv1.PXCScheduledBackup)
+	}
+
+	if len(params.BackupStorages) != 0 {
+		storages := make(map[string]*pxcv1.BackupStorageSpec, len(params.BackupStorages))
+		for _, storage := range params.BackupStorages {
+			if storage.Name == "" {
+				return errors.Wrap(ErrInvalidBackupStorage, "storage name is empty")
+			}
+			if _, ok := storages[storage.Name]; ok {
+				return errors.Wrapf(ErrInvalidBackupStorage, "storage %q is defined twice", storage.Name)
+			}
+			switch storage.Type {
+			case BackupStorageFilesystem:
+				diskSize := storage.DiskSize
+				if diskSize == "" && params.PXC != nil {
+					diskSize = params.PXC.DiskSize
+				}
+				if _, err := resource.ParseQuantity(diskSize); err != nil {
+					return errors.Wrapf(ErrInvalidBackupStorage, "storage %q has invalid disk size %q", storage.Name, diskSize)
+				}
+				storages[storage.Name] = &pxcv1.BackupStorageSpec{
+					Type:   pxcv1.BackupStorageFilesystem,
+					Volume: c.pxcVolumeSpec(diskSize),
+				}
+			case BackupStorageS3:
+				if storage.Bucket == "" || storage.CredentialsSecret == "" {
+					return errors.Wrapf(ErrInvalidBackupStorage, "S3 storage %q requires bucket and credentials secret", storage.Name)
+				}
+				storages[storage.Name] = &pxcv1.BackupStorageSpec{
+					Type: pxcv1.BackupStorageS3,
+					S3: &pxcv1.BackupStorageS3Spec{
+						Bucket:            storage.Bucket,
+						CredentialsSecret: storage.CredentialsSecret,
+						Region:            storage.Region,
+						EndpointURL:       storage.EndpointURL,
+					},
+				}
+			default:
+				return errors.Wrapf(ErrInvalidBackupStorage, "storage %q has unknown type %q", storage.Name, storage.Type)
+			}
+		}
+		spec.Spec.Backup.Storages = storages
+	}
+
+	schedules := spec.Spec.Backup.Schedule
+	if len(params.BackupSchedules) != 0 {
+		schedules = make([]pxcv1.PXCScheduledBackupSchedule, 0, len(params.BackupSchedules))
+		for _, schedule := range params.BackupSchedules {
+			schedules = append(schedules, pxcv1.PXCScheduledBackupSchedule{
+				Name:        schedule.Name,
+				Schedule:    schedule.Schedule,
+				Keep:        schedule.Keep,
+				StorageName: schedule.StorageName,
+			})
+		}
+	}
+	for _, schedule := range schedules {
+		if _, ok := spec.Spec.Backup.Storages[schedule.StorageName]; !ok {
+			return errors.Wrapf(ErrInvalidBackupStorage, "schedule %q references unknown storage %q", schedule.Name, schedule.StorageName)
+		}
+	}
+	spec.Spec.Backup.Schedule = schedules
+	return nil
+}
+
+// S3StorageParams contains parameters of S3 backup storage.
+type S3StorageParams struct {
+	Bucket string
+	Region string
+	// EndpointURL is an URL of S3 compatible storage, AWS is used if empty.
+	EndpointURL string
+	// CredentialsSecret is a name of secret with AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY keys,
+	// the same one backup storage uses.
+	CredentialsSecret string
+}
+
+// ValidateS3Storage checks that bucket is reachable and writable with given credentials.
+// The check runs in a short-lived pod, so it has the same network access as backup jobs.
+// ErrS3StorageCheckFailed with the pod output is returned on auth, endpoint or region problems.
+func (c *K8sClient) ValidateS3Storage(ctx context.Context, s3 S3StorageParams) error {
+	ctx = c.withRequestLogger(ctx, "ValidateS3Storage", "")
+	if s3.Bucket == "" || s3.CredentialsSecret == "" {
+		return errors.Wrap(ErrInvalidBackupStorage, "S3 storage requires bucket and credentials secret")
+	}
+	if _, err := c.kube.GetSecret(ctx, s3.CredentialsSecret); err != nil {
+		if apiErrors.IsNotFound(err) {
+			return errors.Wrapf(ErrNotFound, "secret %q", s3.CredentialsSecret)
+		}
+		return errors.Wrap(err, "cannot get S3 credentials secret")
+	}
+
+	image := c.s3CheckImage
+	if image == "" {
+		image = defaultS3CheckImage
+	}
+	pod := s3CheckPod("dbaas-s3-check-"+uuid.New().String()[:8], image, s3)
+	if err := c.kube.Apply(ctx, pod); err != nil {
+		return errors.Wrap(err, "cannot create S3 check pod")
+	}
+	defer func() {
+		// Request context may be already canceled, but the pod should be removed anyway.
+		ctx, cancel := context.WithTimeout(context.Background(), s3CheckDeleteTimeout)
+		defer cancel()
+		if err := c.kube.Delete(ctx, pod); err != nil {
+			c.requestLogger(ctx).Warnf("cannot delete S3 check pod %q: %v", pod.Name, err)
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(ctx, s3CheckTimeout)
+	defer cancel()
+	var phase corev1.PodPhase
+	err := wait.PollImmediateUntilWithContext(ctx, 2*time.Second, func(ctx context.Context) (bool, error) {
+		p, err := c.kube.GetPod(ctx, pod.Name)
+		if err != nil {
+			return false, nil //nolint:nilerr
+		}
+		phase = p.Status.Phase
+		return phase == corev1.PodSucceeded || phase == corev1.PodFailed, nil
+	})
+	if err != nil {
+		return errors.Wrapf(err, "S3 check pod didn't finish, last phase %q", phase)
+	}
+	if phase == corev1.PodSucceeded {
+		return nil
+	}
+	logs, err := c.kube.GetLogs(ctx, pod.Name, "", 20, false)
+	if err != nil {
+		return errors.Wrap(ErrS3StorageCheckFailed, "cannot get S3 check output")
+	}
+	return errors.Wrap(ErrS3StorageCheckFailed, strings.TrimSpace(logs))
+}
+
+// s3CheckPod returns pod which checks bucket access with HeadBucket and writes and removes a test object.
+// The image should contain AWS CLI.
+func s3CheckPod(name, image string, s3 S3StorageParams) *corev1.Pod {
+	env := []corev1.EnvVar{{Name: "BUCKET", Value: s3.Bucket}}
+	for _, key := range []string{"AWS_ACCESS_KEY_ID", "AWS_SECRET_ACCESS_KEY"} {
+		env = append(env, corev1.EnvVar{
+			Name: key,
+			ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: s3.CredentialsSecret},
+				Key:                  key,
+			}},
+		})
+	}
+	if s3.Region != "" {
+		env = append(env, corev1.EnvVar{Name: "AWS_DEFAULT_REGION", Value: s3.Region})
+	}
+	if s3.EndpointURL != "" {
+		env = append(env, corev1.EnvVar{Name: "AWS_ENDPOINT_URL", Value: s3.EndpointURL})
+	}
+	script := `set -e
+aws s3api head-bucket --bucket "$BUCKET"
+echo ok | aws s3 cp - "s3://$BUCKET/.dbaas-s3-check"
+aws s3 rm "s3://$BUCKET/.dbaas-s3-check"`
+
+	return &corev1.Pod{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: k8sAPIVersion,
+			Kind:       k8sMetaKindPod,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{managedByLabel: managedByValue},
+		},
+		Spec: corev1.PodSpec{
+			RestartPolicy: corev1.RestartPolicyNever,
+			Containers: []corev1.Container{{
+				Name:    "s3-check",
+				Image:   image,
+				Command: []string{"/bin/sh", "-c", script},
+				Env:     env,
+			}},
+		},
+	}
+}
+
+// setManagedByLabel marks custom resource as created by dbaas-controller.
+func setManagedByLabel(meta *metav1.ObjectMeta) {
+	if meta.Labels == nil {
+		meta.Labels = make(map[string]string, 1)
+	}
+	meta.Labels[managedByLabel] = managedByValue
+}
+
+// isManagedByDBaaS returns true if custom resource was created by dbaas-controller.
+func isManagedByDBaaS(meta *metav1.ObjectMeta) bool {
+	return meta.Labels[managedByLabel] == managedByValue
+}
+
+// applyOverrides deep-merges overrides into given custom resource using JSON round-trip.
+// Nested maps are merged key by key, any other value replaces existing one.
+func applyOverrides(spec interface{}, overrides map[string]interface{}) error {
+	if len(overrides) == 0 {
+		return nil
+	}
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return errors.Wrap(err, "cannot marshal custom resource")
+	}
+	var obj map[string]interface{}
+	if err = json.Unmarshal(data, &obj); err != nil {
+		return errors.Wrap(err, "cannot unmarshal custom resource")
+	}
+	mergeMaps(obj, convert(overrides).(map[string]interface{}))
+	data, err = json.Marshal(obj)
+	if err != nil {
+		return errors.Wrap(err, "cannot marshal overridden custom resource")
+	}
+	return errors.Wrap(json.Unmarshal(data, spec), "cannot apply overrides to custom resource")
+}
 
+// mergeMaps recursively merges src into dst.
+func mergeMaps(dst, src map[string]interface{}) {
+	for k, v := range src {
+		srcMap, srcOK := v.(map[string]interface{})
+		dstMap, dstOK := dst[k].(map[string]interface{})
+		if srcOK && dstOK {
+			mergeMaps(dstMap, srcMap)
+			continue
+		}
+		dst[k] = v
 	}
-	c.l.Debug("failed openint cr template file. Fallback to defaults")
-	return c.getDefaultPXCSpec(params, *secretName, pxcOperatorVersion, storageName, serviceType), nil
 }
 
-func (c *K8sClient) overridePSMDBSpec(spec *psmdbv1.PerconaServerMongoDB, params *PSMDBParams, extra extraCRParams) *psmdbv1.PerconaServerMongoDB {
+func (c *K8sClient) overridePSMDBSpec(spec *psmdbv1.PerconaServerMongoDB, params *PSMDBParams, extra extraCRParams) (*psmdbv1.PerconaServerMongoDB, error) {
 	spec.Spec.Image = extra.psmdbImage
 	spec.ObjectMeta.Name = params.Name
-	spec.Spec.Sharding.ConfigsvrReplSet.Size = params.Size
+	spec.Spec.Sharding.ConfigsvrReplSet.Size = psmdbConfigServerSize(params)
 
 	spec.Spec.Replsets[0].Resources = c.setComputeResources(params.Replicaset.ComputeResources)
 	spec.Spec.Sharding.Mongos.Resources = c.setComputeResources(params.Replicaset.ComputeResources)
 	spec.Spec.Sharding.ConfigsvrReplSet.VolumeSpec = c.volumeSpec(params.Replicaset.DiskSize)
+	if params.ReplsetName != "" {
+		spec.Spec.Replsets[0].Name = params.ReplsetName
+	}
 	// FIXME: implement better solution
 	if spec.Spec.Backup.Image == "" {
 		spec.Spec.Backup = psmdbv1.BackupSpec{
@@ -2141,6 +5988,42 @@ func (c *K8sClient) overridePSMDBSpec(spec *psmdbv1.PerconaServerMongoDB, params
 	if !params.Expose {
 		spec.Spec.Sharding.Mongos.Expose.ExposeType = corev1.ServiceTypeClusterIP
 	}
+	if params.SchedulerName != "" {
+		spec.Spec.SchedulerName = params.SchedulerName
+	}
+	if params.MongoPort != 0 {
+		if spec.Spec.Mongod == nil {
+			spec.Spec.Mongod = new(psmdbv1.MongodSpec)
+		}
+		if spec.Spec.Mongod.Net == nil {
+			spec.Spec.Mongod.Net = new(psmdbv1.MongodSpecNet)
+		}
+		spec.Spec.Mongod.Net.Port = params.MongoPort
+		if spec.Spec.Sharding.Mongos != nil {
+			spec.Spec.Sharding.Mongos.Port = params.MongoPort
+		}
+	}
+	if params.ProfilingMode != "" || params.SlowOpThresholdMs != 0 || params.RateLimit != 0 {
+		if spec.Spec.Mongod == nil {
+			spec.Spec.Mongod = new(psmdbv1.MongodSpec)
+		}
+		profiling := operationProfiling(params)
+		spec.Spec.Mongod.OperationProfiling = profiling
+		conf, err := mergeProfilingConfiguration(spec.Spec.Replsets[0].Configuration, profiling)
+		if err != nil {
+			return nil, err
+		}
+		spec.Spec.Replsets[0].Configuration = conf
+	}
+	if params.Encryption != nil || params.EncryptionKeySecret != "" {
+		if spec.Spec.Mongod == nil {
+			spec.Spec.Mongod = new(psmdbv1.MongodSpec)
+		}
+		if spec.Spec.Mongod.Security == nil {
+			spec.Spec.Mongod.Security = new(psmdbv1.MongodSpecSecurity)
+		}
+		setEncryption(spec.Spec.Mongod.Security, params)
+	}
 
 	if params.Size == 1 {
 		spec.Spec.UnsafeConf = true
@@ -2156,16 +6039,21 @@ func (c *K8sClient) overridePSMDBSpec(spec *psmdbv1.PerconaServerMongoDB, params
 			Enabled:    true,
 			ServerHost: params.PMM.PublicAddress,
 			Image:      pmmClientImage,
-			Resources: corev1.ResourceRequirements{
-				Requests: corev1.ResourceList{
-					corev1.ResourceMemory: resource.MustParse("300M"),
-					corev1.ResourceCPU:    resource.MustParse("500m"),
-				},
-			},
+			Resources:  pmmClientResources(params.PMM.Resources),
 		}
 	}
 
-	return spec
+	return spec, nil
+}
+
+// pxcBackupImage returns backup image from params if set.
+// Starting with operator 1.12, the image name doesn't follow a template rule anymore,
+// so the template is used only as a fallback.
+func pxcBackupImage(params *PXCParams, pxcOperatorVersion string) string {
+	if params.BackupImage != "" {
+		return params.BackupImage
+	}
+	return fmt.Sprintf(pxcBackupImageTemplate, pxcOperatorVersion)
 }
 
 func (c *K8sClient) overridePXCSpec(spec *pxcv1.PerconaXtraDBCluster, params *PXCParams, storageName, pxcOperatorVersion string) *pxcv1.PerconaXtraDBCluster {
@@ -2184,7 +6072,7 @@ func (c *K8sClient) overridePXCSpec(spec *pxcv1.PerconaXtraDBCluster, params *PX
 	}
 	if spec.Spec.Backup == nil {
 		spec.Spec.Backup = &pxcv1.PXCScheduledBackup{
-			Image: fmt.Sprintf(pxcBackupImageTemplate, pxcOperatorVersion),
+			Image: pxcBackupImage(params, pxcOperatorVersion),
 			Schedule: []pxcv1.PXCScheduledBackupSchedule{{
 				Name:        "test",
 				Schedule:    "*/30 * * * *",
@@ -2200,8 +6088,8 @@ func (c *K8sClient) overridePXCSpec(spec *pxcv1.PerconaXtraDBCluster, params *PX
 			ServiceAccountName: "percona-xtradb-cluster-operator",
 		}
 	}
-	if spec.Spec.Backup.Image == "" {
-		spec.Spec.Backup.Image = fmt.Sprintf(pxcBackupImageTemplate, pxcOperatorVersion)
+	if spec.Spec.Backup.Image == "" || params.BackupImage != "" {
+		spec.Spec.Backup.Image = pxcBackupImage(params, pxcOperatorVersion)
 	}
 	if len(spec.Spec.Backup.Storages) == 0 {
 		spec.Spec.Backup.Storages = map[string]*pxcv1.BackupStorageSpec{
@@ -2224,6 +6112,15 @@ func (c *K8sClient) overridePXCSpec(spec *pxcv1.PerconaXtraDBCluster, params *PX
 			spec.Spec.HAProxy.Image = params.HAProxy.Image
 		}
 	}
+	if params.SchedulerName != "" {
+		spec.Spec.PXC.PodSpec.SchedulerName = params.SchedulerName
+		if spec.Spec.ProxySQL != nil {
+			spec.Spec.ProxySQL.SchedulerName = params.SchedulerName
+		}
+		if spec.Spec.HAProxy != nil {
+			spec.Spec.HAProxy.SchedulerName = params.SchedulerName
+		}
+	}
 	// Always override defaults for PMM by specified by user
 	if params.PMM != nil {
 		spec.Spec.PMM = &pxcv1.PMMSpec{
@@ -2232,12 +6129,7 @@ func (c *K8sClient) overridePXCSpec(spec *pxcv1.PerconaXtraDBCluster, params *PX
 			ServerUser:      params.PMM.Login,
 			Image:           pmmClientImage,
 			ImagePullPolicy: corev1.PullPolicy(string(pullPolicy)),
-			Resources: corev1.ResourceRequirements{
-				Requests: corev1.ResourceList{
-					corev1.ResourceMemory: resource.MustParse("300M"),
-					corev1.ResourceCPU:    resource.MustParse("500m"),
-				},
-			},
+			Resources:       pmmClientResources(params.PMM.Resources),
 		}
 	}
 
@@ -2257,12 +6149,12 @@ func (c *K8sClient) getDefaultPXCSpec(params *PXCParams, secretName, pxcOperator
 		},
 		ObjectMeta: metav1.ObjectMeta{
 			Name:       params.Name,
-			Finalizers: []string{"delete-proxysql-pvc", "delete-pxc-pvc"},
+			Finalizers: []string{pxcProxySQLPVCFinalizer, pxcPVCFinalizer},
 		},
 		Spec: pxcv1.PerconaXtraDBClusterSpec{
 			UpdateStrategy:    updateStrategyRollingUpdate,
 			CRVersion:         pxcOperatorVersion,
-			AllowUnsafeConfig: true,
+			AllowUnsafeConfig: pxcAllowUnsafeConfig(params),
 			SecretsName:       secretName,
 
 			PXC: &pxcv1.PXCSpec{
@@ -2272,6 +6164,7 @@ func (c *K8sClient) getDefaultPXCSpec(params *PXCParams, secretName, pxcOperator
 					Image:           pxcImage,
 					ImagePullPolicy: corev1.PullPolicy(string(pullPolicy)),
 					VolumeSpec:      c.pxcVolumeSpec(params.PXC.DiskSize),
+					SchedulerName:   params.SchedulerName,
 					Affinity: &pxcv1.PodAffinity{
 						TopologyKey: pointer.ToString(pxcv1.AffinityTopologyKeyOff),
 					},
@@ -2286,7 +6179,7 @@ func (c *K8sClient) getDefaultPXCSpec(params *PXCParams, secretName, pxcOperator
 			},
 
 			Backup: &pxcv1.PXCScheduledBackup{
-				Image: fmt.Sprintf(pxcBackupImageTemplate, pxcOperatorVersion),
+				Image: pxcBackupImage(params, pxcOperatorVersion),
 				Schedule: []pxcv1.PXCScheduledBackupSchedule{{
 					Name:        "test",
 					Schedule:    "*/30 * * * *",
@@ -2311,12 +6204,7 @@ func (c *K8sClient) getDefaultPXCSpec(params *PXCParams, secretName, pxcOperator
 			ServerUser:      params.PMM.Login,
 			Image:           pmmClientImage,
 			ImagePullPolicy: corev1.PullPolicy(string(pullPolicy)),
-			Resources: corev1.ResourceRequirements{
-				Requests: corev1.ResourceList{
-					corev1.ResourceMemory: resource.MustParse("300M"),
-					corev1.ResourceCPU:    resource.MustParse("500m"),
-				},
-			},
+			Resources:       pmmClientResources(params.PMM.Resources),
 		}
 	}
 
@@ -2324,6 +6212,7 @@ func (c *K8sClient) getDefaultPXCSpec(params *PXCParams, secretName, pxcOperator
 		Enabled:         true,
 		ImagePullPolicy: corev1.PullPolicy(string(pullPolicy)),
 		Size:            params.Size,
+		SchedulerName:   params.SchedulerName,
 		Affinity: &pxcv1.PodAffinity{
 			TopologyKey: pointer.ToString(pxcv1.AffinityTopologyKeyOff),
 		},
@@ -2374,6 +6263,12 @@ func convert(i interface{}) interface{} {
 			m2[k.(string)] = convert(v)
 		}
 		return m2
+	case map[string]interface{}:
+		m2 := make(map[string]interface{}, len(x))
+		for k, v := range x {
+			m2[k] = convert(v)
+		}
+		return m2
 	case []interface{}:
 		for i, v := range x {
 			x[i] = convert(v)
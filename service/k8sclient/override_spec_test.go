@@ -66,9 +66,10 @@ func TestPXCSpec(t *testing.T) {
 		t.Parallel()
 		secret := "secret"
 
-		spec, err := client.createPXCSpecFromParams(params, &secret, "1.11.0", "storage", "")
+		spec, err := client.createPXCSpecFromParams(ctx, params, &secret, "1.11.0", "storage", "")
 		assert.NoError(t, err)
 		defaultSpec := client.getDefaultPXCSpec(params, "secret", "1.11.0", "storage", "")
+		setManagedByLabel(&defaultSpec.ObjectMeta)
 		assert.Equal(t, defaultSpec, spec)
 	})
 
@@ -213,12 +214,14 @@ func TestPSMDBSpec(t *testing.T) {
 	assert.NoError(t, err)
 	t.Run("should fallback to default spec once template does not exist", func(t *testing.T) {
 		t.Parallel()
-		spec, err := client.createPSMDBSpec(operator, params, &extra)
+		spec, err := client.createPSMDBSpec(ctx, operator, params, &extra)
 		assert.NoError(t, err)
 		defaultSpec := client.getPSMDBSpec(params, extra)
+		setManagedByLabel(&defaultSpec.ObjectMeta)
 		assert.Equal(t, defaultSpec, spec)
 		params.Expose = false
-		spec = client.overridePSMDBSpec(spec, params, extra)
+		spec, err = client.overridePSMDBSpec(spec, params, extra)
+		assert.NoError(t, err)
 		assert.Equal(t, corev1.ServiceTypeClusterIP, spec.Spec.Sharding.Mongos.Expose.ExposeType)
 	})
 }
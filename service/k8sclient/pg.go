@@ -0,0 +1,387 @@
+// dbaas-controller
+// Copyright (C) 2020 Percona LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package k8sclient
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/percona-platform/dbaas-controller/service/k8sclient/internal/kube"
+	"github.com/percona-platform/dbaas-controller/service/k8sclient/internal/kube/pg"
+)
+
+const (
+	pgAPIVersion               = "pg.percona.com/v1"
+	pgDefaultImage             = "percona/percona-postgresql-operator:1.3.0-ppg14-postgres-ha"
+	pgBouncerDefaultImage      = "percona/percona-postgresql-operator:1.3.0-ppg14-pgbouncer"
+	pgBackrestImage            = "percona/percona-postgresql-operator:1.3.0-ppg14-pgbackrest"
+	pgBackrestRepoImage        = "percona/percona-postgresql-operator:1.3.0-ppg14-pgbackrest-repo"
+	pgSecretNameTmpl           = "%s-users" //nolint:gosec
+	pgPMMSecretNameTmpl        = "%s-pmm-secret"
+	pgDatabaseName             = "pgdb"
+	pgUserName                 = "pguser"
+	pgPort                     = 5432
+	pgDefaultDiskSize          = "1G"
+	pgVolumeAccessMode         = "ReadWriteOnce"
+	pgVolumeStorageTypeDynamic = "dynamic"
+)
+
+// ErrPGClusterNotReady The PostgreSQL cluster is not ready.
+var ErrPGClusterNotReady = errors.New("PostgreSQL cluster is not ready")
+
+// PostgreSQL contains information related to PostgreSQL containers in Percona Distribution for PostgreSQL cluster.
+type PostgreSQL struct {
+	Image            string
+	ComputeResources *ComputeResources
+	DiskSize         string
+}
+
+// PGBouncer contains information related to pgBouncer containers in Percona Distribution for PostgreSQL cluster.
+type PGBouncer struct {
+	Image            string
+	ComputeResources *ComputeResources
+}
+
+// PGParams contains all parameters required to create Percona Distribution for PostgreSQL cluster.
+type PGParams struct {
+	Name       string
+	Size       int32
+	Expose     bool
+	PostgreSQL *PostgreSQL
+	PGBouncer  *PGBouncer
+	PMM        *PMM
+}
+
+// PGCluster contains information related to PostgreSQL cluster.
+type PGCluster struct {
+	Name       string
+	Message    string
+	Size       int32
+	Pause      bool
+	Exposed    bool
+	State      ClusterState
+	PostgreSQL *PostgreSQL
+	PGBouncer  *PGBouncer
+	// CreatedAt is a creation time of cluster custom resource.
+	CreatedAt time.Time
+}
+
+// PGCredentials represents PostgreSQL connection credentials.
+type PGCredentials struct {
+	Username string
+	Password string
+	Host     string
+	Port     int32
+	Database string
+}
+
+// ConnectionURI returns PostgreSQL connection string with URL-escaped credentials.
+func (c *PGCredentials) ConnectionURI() string {
+	u := url.URL{
+		Scheme: "postgresql",
+		User:   url.UserPassword(c.Username, c.Password),
+		Host:   net.JoinHostPort(c.Host, strconv.Itoa(int(c.Port))),
+		Path:   "/" + c.Database,
+	}
+	return u.String()
+}
+
+// ListPGClusters returns list of Percona Distribution for PostgreSQL clusters.
+func (c *K8sClient) ListPGClusters(ctx context.Context) ([]PGCluster, error) {
+	ctx = c.withRequestLogger(ctx, "ListPGClusters", "")
+	list, err := c.kube.ListPGClusters(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "couldn't get PostgreSQL clusters")
+	}
+
+	res := make([]PGCluster, len(list.Items))
+	crAndPodsMatchFunc := c.newBatchCRVersionMatcher("pgo-pg-database=true", "pg-cluster")
+	for i, cluster := range list.Items {
+		val := PGCluster{
+			Name:      cluster.Name,
+			Size:      cluster.Status.Size,
+			Pause:     cluster.Spec.Pause,
+			Message:   cluster.Status.PGCluster.Message,
+			CreatedAt: cluster.CreationTimestamp.Time,
+		}
+		if cluster.Spec.PGPrimary != nil {
+			val.PostgreSQL = &PostgreSQL{
+				Image:            cluster.Spec.PGPrimary.Image,
+				ComputeResources: c.getComputeResources(cluster.Spec.PGPrimary.Resources),
+			}
+			if cluster.Spec.PGPrimary.VolumeSpec != nil {
+				val.PostgreSQL.DiskSize = cluster.Spec.PGPrimary.VolumeSpec.Size
+			}
+			val.Exposed = isExposed(cluster.Spec.PGPrimary.Expose.ServiceType, nil)
+		}
+		if cluster.Spec.PGBouncer != nil {
+			val.PGBouncer = &PGBouncer{
+				Image:            cluster.Spec.PGBouncer.Image,
+				ComputeResources: c.getComputeResources(cluster.Spec.PGBouncer.Resources),
+			}
+			val.Exposed = isExposed(cluster.Spec.PGBouncer.Expose.ServiceType, nil)
+		}
+		if val.Size == 0 {
+			val.Size = 1
+			if cluster.Spec.PGReplicas != nil && cluster.Spec.PGReplicas.HotStandby != nil {
+				val.Size += cluster.Spec.PGReplicas.HotStandby.Size
+			}
+		}
+
+		clusterInfo := kube.NewDBClusterInfoFromPG(&cluster)
+		val.State = c.getClusterState(ctx, clusterInfo, crAndPodsMatchFunc)
+		res[i] = val
+	}
+	return res, nil
+}
+
+// CreatePGCluster creates Percona Distribution for PostgreSQL cluster with provided parameters.
+func (c *K8sClient) CreatePGCluster(ctx context.Context, params *PGParams) error {
+	ctx = c.withRequestLogger(ctx, "CreatePGCluster", params.Name)
+	l := c.requestLogger(ctx)
+	l.Debug("creating cluster")
+
+	err := validatePMMParams(params.PMM)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.kube.GetPGCluster(ctx, params.Name)
+	if err == nil {
+		return fmt.Errorf(clusterWithSameNameExistsErrTemplate, params.Name)
+	}
+
+	secretName := fmt.Sprintf(pgSecretNameTmpl, params.Name)
+	secrets, err := generatePGPasswords()
+	if err != nil {
+		return err
+	}
+
+	serviceType := corev1.ServiceTypeClusterIP
+	if params.Expose {
+		// See CreatePXCCluster, the same limitations are applied to minikube.
+		serviceType = corev1.ServiceTypeNodePort
+		if clusterType := c.GetKubernetesClusterType(ctx); clusterType != MinikubeClusterType {
+			serviceType = corev1.ServiceTypeLoadBalancer
+		}
+	}
+
+	spec := c.getPGSpec(params, secretName, serviceType)
+
+	err = c.CreateSecret(ctx, secretName, secrets)
+	if err != nil {
+		return errors.Wrap(err, "cannot create secret for PostgreSQL")
+	}
+
+	if params.PMM != nil {
+		err = c.CreateSecret(ctx, spec.Spec.PMM.PMMSecret, map[string][]byte{
+			"username": []byte(params.PMM.Login),
+			"password": []byte(params.PMM.Password),
+		})
+		if err != nil {
+			return errors.Wrap(err, "cannot create PMM secret for PostgreSQL")
+		}
+	}
+
+	return c.applyWithRetry(ctx, spec)
+}
+
+// DeletePGCluster deletes Percona Distribution for PostgreSQL cluster with provided name.
+func (c *K8sClient) DeletePGCluster(ctx context.Context, name string) error {
+	ctx = c.withRequestLogger(ctx, "DeletePGCluster", name)
+	l := c.requestLogger(ctx)
+	l.Debug("deleting cluster")
+
+	spec := &pg.PerconaPGCluster{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: pgAPIVersion,
+			Kind:       kube.PGKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+	}
+	err := c.kube.Delete(ctx, spec)
+	if err != nil {
+		return errors.Wrap(err, "cannot delete PostgreSQL")
+	}
+
+	for _, secretTmpl := range []string{pgSecretNameTmpl, pgPMMSecretNameTmpl} {
+		err = c.deleteSecret(ctx, fmt.Sprintf(secretTmpl, name))
+		if err != nil {
+			l.Errorf("cannot delete secret for %s: %v", name, err)
+		}
+	}
+
+	return nil
+}
+
+// GetPGClusterCredentials returns Percona Distribution for PostgreSQL cluster credentials.
+func (c *K8sClient) GetPGClusterCredentials(ctx context.Context, name string) (*PGCredentials, error) {
+	ctx = c.withRequestLogger(ctx, "GetPGClusterCredentials", name)
+	cluster, err := c.kube.GetPGCluster(ctx, name)
+	if err != nil {
+		if apiErrors.IsNotFound(err) {
+			return nil, errors.Wrap(ErrNotFound, fmt.Sprintf(canNotGetCredentialsErrTemplate, "PostgreSQL"))
+		}
+		return nil, errors.Wrap(err, fmt.Sprintf(canNotGetCredentialsErrTemplate, "PostgreSQL"))
+	}
+
+	clusterInfo := kube.NewDBClusterInfoFromPG(cluster)
+	clusterState := c.getClusterState(ctx, clusterInfo, c.crVersionMatchesPodsVersion)
+	if clusterState != ClusterStateReady {
+		return nil, errors.Wrap(ErrPGClusterNotReady, fmt.Sprintf(canNotGetCredentialsErrTemplate, "PostgreSQL"))
+	}
+
+	secretName := cluster.Spec.SecretsName
+	if secretName == "" {
+		secretName = fmt.Sprintf(pgSecretNameTmpl, name)
+	}
+	secret, err := c.kube.GetSecret(ctx, secretName)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot get PostgreSQL cluster secrets")
+	}
+
+	// Clients connect through pgBouncer if it is enabled and to the primary otherwise.
+	serviceName := cluster.Name
+	if cluster.Spec.PGBouncer != nil && cluster.Spec.PGBouncer.Size > 0 {
+		serviceName = cluster.Name + "-pgbouncer"
+	}
+	service, err := c.kube.GetService(ctx, serviceName)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot get PostgreSQL cluster service")
+	}
+
+	return &PGCredentials{
+		Username: cluster.Spec.User,
+		Password: string(secret.Data[cluster.Spec.User]),
+		Host:     pgServiceHost(service),
+		Port:     pgPort,
+		Database: cluster.Spec.Database,
+	}, nil
+}
+
+// pgServiceHost returns external address of exposed service or internal DNS name otherwise.
+func pgServiceHost(service *corev1.Service) string {
+	for _, ingress := range service.Status.LoadBalancer.Ingress {
+		if ingress.Hostname != "" {
+			return ingress.Hostname
+		}
+		if ingress.IP != "" {
+			return ingress.IP
+		}
+	}
+	return fmt.Sprintf("%s.%s.svc", service.Name, service.Namespace)
+}
+
+func (c *K8sClient) getPGSpec(params *PGParams, secretName string, serviceType corev1.ServiceType) *pg.PerconaPGCluster {
+	diskSize := pgDefaultDiskSize
+	image := pgDefaultImage
+	var resources corev1.ResourceRequirements
+	if params.PostgreSQL != nil {
+		if params.PostgreSQL.DiskSize != "" {
+			diskSize = params.PostgreSQL.DiskSize
+		}
+		if params.PostgreSQL.Image != "" {
+			image = params.PostgreSQL.Image
+		}
+		resources = c.setComputeResources(params.PostgreSQL.ComputeResources)
+	}
+	volumeSpec := &pg.VolumeSpec{
+		Size:        diskSize,
+		AccessMode:  pgVolumeAccessMode,
+		StorageType: pgVolumeStorageTypeDynamic,
+	}
+
+	spec := &pg.PerconaPGCluster{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: pgAPIVersion,
+			Kind:       kube.PGKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: params.Name,
+		},
+		Spec: pg.PerconaPGClusterSpec{
+			Database:    pgDatabaseName,
+			Port:        fmt.Sprint(pgPort),
+			User:        pgUserName,
+			SecretsName: secretName,
+			PGPrimary: &pg.PGPrimary{
+				Image:      image,
+				Resources:  resources,
+				VolumeSpec: volumeSpec,
+				Expose:     pg.Expose{ServiceType: serviceType},
+			},
+			Backup: &pg.Backup{
+				Image:             pgBackrestImage,
+				BackrestRepoImage: pgBackrestRepoImage,
+				VolumeSpec:        volumeSpec,
+			},
+			PMM: &pg.PMMSpec{
+				Enabled: false,
+			},
+		},
+	}
+
+	if params.Size > 1 {
+		spec.Spec.PGReplicas = &pg.PGReplicas{
+			HotStandby: &pg.HotStandby{
+				Size:       params.Size - 1,
+				Resources:  resources,
+				VolumeSpec: volumeSpec,
+				Expose:     pg.Expose{ServiceType: corev1.ServiceTypeClusterIP},
+			},
+		}
+	}
+
+	if params.PGBouncer != nil {
+		bouncerImage := pgBouncerDefaultImage
+		if params.PGBouncer.Image != "" {
+			bouncerImage = params.PGBouncer.Image
+		}
+		spec.Spec.PGBouncer = &pg.PGBouncer{
+			Image:     bouncerImage,
+			Size:      params.Size,
+			Resources: c.setComputeResources(params.PGBouncer.ComputeResources),
+			Expose:    pg.Expose{ServiceType: serviceType},
+		}
+		// Primary is reachable through pgBouncer only.
+		spec.Spec.PGPrimary.Expose.ServiceType = corev1.ServiceTypeClusterIP
+	}
+
+	if params.PMM != nil {
+		spec.Spec.PMM = &pg.PMMSpec{
+			Enabled:    true,
+			Image:      pmmClientImage,
+			ServerHost: params.PMM.PublicAddress,
+			ServerUser: params.PMM.Login,
+			PMMSecret:  fmt.Sprintf(pgPMMSecretNameTmpl, params.Name),
+			Resources:  pmmClientResources(params.PMM.Resources),
+		}
+	}
+
+	return spec
+}
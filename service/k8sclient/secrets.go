@@ -92,3 +92,16 @@ func generatePSMDBPasswords() (map[string][]byte, error) {
 	}
 	return secrets, nil
 }
+
+func generatePGPasswords() (map[string][]byte, error) {
+	// secrets represents stringData part of
+	// https://github.com/percona/percona-postgresql-operator/blob/main/deploy/users-secret.yaml.
+	secrets := map[string][]byte{
+		"postgres":    {},
+		"primaryuser": {},
+		"pgbouncer":   {},
+		pgUserName:    {},
+	}
+
+	return generatePasswords(secrets)
+}
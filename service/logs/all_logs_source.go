@@ -73,7 +73,7 @@ func (a *allLogsSource) getLogs(
 		for _, t := range tuples {
 			for _, container := range t.containers {
 				logs, err := client.GetLogs(
-					ctx, t.statuses, pod.Name, container.Name)
+					ctx, t.statuses, pod.Name, container.Name, false, k8sclient.DefaultLogTailLines)
 				if err != nil {
 					return nil, status.Error(
 						codes.Internal,
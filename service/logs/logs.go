@@ -34,6 +34,7 @@ import (
 type Service struct {
 	defaultSource source
 	sources       []source
+	clientOpts    []k8sclient.Option
 }
 
 // Thanks to source interface we can get logs from different sources.
@@ -42,17 +43,19 @@ type source interface {
 }
 
 // NewService creates a new instance of Service.
-func NewService() *Service {
+// Given options are applied to Kubernetes clients created for requests.
+func NewService(opts ...k8sclient.Option) *Service {
 	return &Service{
 		defaultSource: source(new(allLogsSource)),
 		sources:       []source{},
+		clientOpts:    opts,
 	}
 }
 
 // GetLogs first tries to get logs and events only from failing pods/containers.
 // If no such logs/events are found, it returns logs from the defaultSource.
 func (s *Service) GetLogs(ctx context.Context, req *controllerv1beta1.GetLogsRequest) (*controllerv1beta1.GetLogsResponse, error) {
-	client, err := k8sclient.New(ctx, req.KubeAuth.Kubeconfig)
+	client, err := k8sclient.New(ctx, req.KubeAuth.Kubeconfig, s.clientOpts...)
 	if err != nil {
 		return nil, status.Error(codes.Internal, "Cannot initialize K8s client: "+err.Error())
 	}
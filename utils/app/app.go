@@ -19,6 +19,7 @@ package app
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/percona/pmm/version"
 	"gopkg.in/alecthomas/kingpin.v2"
@@ -39,6 +40,21 @@ type Flags struct {
 	PSMDBOperatorURLTemplate string
 	// Debug enabled.
 	LogDebug bool
+	// ShutdownTimeout is the maximum time to wait for in-flight requests on shutdown.
+	ShutdownTimeout time.Duration
+	// HTTPTimeout is the timeout of quick HTTP requests such as listing available operator versions.
+	HTTPTimeout time.Duration
+	// ManifestFetchTimeout is the timeout of operator manifests downloads.
+	ManifestFetchTimeout time.Duration
+	// KubeConnectionPool configures idle connections of Kubernetes API clients, zero fields keep defaults.
+	KubeConnectionPool ConnectionPool
+}
+
+// ConnectionPool configures idle HTTP connections kept for reuse.
+type ConnectionPool struct {
+	MaxIdleConns        int
+	MaxIdleConnsPerHost int
+	IdleConnTimeout     time.Duration
 }
 
 // SetupOpts contains options required for app.
@@ -84,6 +100,35 @@ func Setup(opts *SetupOpts) (*Flags, error) {
 		DefaultPSMDBOperatorURLTemplate,
 	).StringVar(&flags.PSMDBOperatorURLTemplate)
 
+	kingpin.Flag(
+		"shutdown.timeout",
+		"Maximum time to wait for in-flight requests (e.g. cluster creation) to finish on SIGTERM/SIGINT; "+
+			"keep it below the pod's terminationGracePeriodSeconds (30s by default).",
+	).Default("25s").DurationVar(&flags.ShutdownTimeout)
+
+	kingpin.Flag(
+		"http.timeout",
+		"Timeout of quick HTTP requests, e.g. listing available operator versions.",
+	).Default("5s").DurationVar(&flags.HTTPTimeout)
+	kingpin.Flag(
+		"operator.manifest.timeout",
+		"Timeout of operator manifests downloads, raise it for slow connections to the manifests server.",
+	).Default("2m").DurationVar(&flags.ManifestFetchTimeout)
+
+	kingpin.Flag(
+		"kube.max-idle-conns",
+		"Maximum number of idle connections to Kubernetes API servers, no limit if zero. Only used over HTTP/1.1.",
+	).IntVar(&flags.KubeConnectionPool.MaxIdleConns)
+	kingpin.Flag(
+		"kube.max-idle-conns-per-host",
+		"Maximum number of idle connections per Kubernetes API server, 25 if zero. Only used over HTTP/1.1: "+
+			"requests above that open new connections with TLS handshake each, so raise it for many concurrent operations.",
+	).IntVar(&flags.KubeConnectionPool.MaxIdleConnsPerHost)
+	kingpin.Flag(
+		"kube.idle-conn-timeout",
+		"Time after which idle connection to Kubernetes API server is closed, 90s if zero.",
+	).DurationVar(&flags.KubeConnectionPool.IdleConnTimeout)
+
 	kingpin.Flag("debug", "Enable debug").Envar("PMM_DEBUG").BoolVar(&flags.LogDebug)
 
 	return &flags, nil
@@ -41,83 +41,113 @@ const (
 	exbiByte uint64 = pebiByte * 1024
 )
 
-// StrToBytes converts string containing memory as string to number of bytes the string represents.
-func StrToBytes(memory string) (uint64, error) {
-	if len(memory) == 0 {
-		return 0, nil
-	}
-	i := len(memory) - 1
-	for i >= 0 && !unicode.IsDigit(rune(memory[i])) {
+// quantitySuffixes maps suffixes of Kubernetes quantities to their multipliers.
+// Supports both decimal (k, M, G, ...) and binary (Ki, Mi, Gi, ...) SI suffixes, see
+// https://kubernetes.io/docs/reference/kubernetes-api/common-definitions/quantity/.
+var quantitySuffixes = map[string]float64{ //nolint:gochecknoglobals
+	"m":  0.001,
+	"":   1.0,
+	"k":  float64(kiloByte),
+	"Ki": float64(kibiByte),
+	"M":  float64(megaByte),
+	"Mi": float64(mibiByte),
+	"G":  float64(gigaByte),
+	"Gi": float64(gibiByte),
+	"T":  float64(teraByte),
+	"Ti": float64(tebiByte),
+	"P":  float64(petaByte),
+	"Pi": float64(pebiByte),
+	"E":  float64(exaByte),
+	"Ei": float64(exbiByte),
+}
+
+// parseQuantity splits quantity into a number and a suffix and returns the number multiplied by suffix multiplier.
+func parseQuantity(quantity string) (float64, error) {
+	i := len(quantity) - 1
+	for i >= 0 && !unicode.IsDigit(rune(quantity[i])) && quantity[i] != '.' {
 		i--
 	}
-	var suffix string
-	if i >= 0 {
-		suffix = memory[i+1:]
-	}
+	number, suffix := quantity[:i+1], quantity[i+1:]
 
-	// Resources units map for k8s
-	//
-	// Supports the following units
-	// https://kubernetes.io/docs/concepts/configuration/manage-resources-containers/#meaning-of-memory
-	//
-	// Support of 'm' unit can be redundant because it's used for CPU limits mostly
-	suffixMapping := map[string]float64{
-		"m":  0.001,
-		"k":  float64(kiloByte),
-		"Ki": float64(kibiByte),
-		"M":  float64(megaByte),
-		"Mi": float64(mibiByte),
-		"G":  float64(gigaByte),
-		"Gi": float64(gibiByte),
-		"T":  float64(teraByte),
-		"Ti": float64(tebiByte),
-		"P":  float64(petaByte),
-		"Pi": float64(pebiByte),
-		"E":  float64(exaByte),
-		"Ei": float64(exbiByte),
-		"":   1.0,
-	}
-	coeficient, ok := suffixMapping[suffix]
+	coeficient, ok := quantitySuffixes[suffix]
 	if !ok {
 		return 0, errors.Errorf("suffix '%s' is not supported", suffix)
 	}
+	value, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0, errors.Errorf("given value '%s' is not a number", number)
+	}
+	if value < 0 {
+		return 0, errors.Errorf("given value '%s' is negative", quantity)
+	}
+	return value * coeficient, nil
+}
 
-	if suffix != "" {
-		memory = memory[:i+1]
+// StrToBytes converts string containing memory as string to number of bytes the string represents.
+// Fractional bytes are rounded up.
+func StrToBytes(memory string) (uint64, error) {
+	if len(memory) == 0 {
+		return 0, nil
 	}
-	value, err := strconv.ParseFloat(memory, 64)
+	value, err := parseQuantity(memory)
 	if err != nil {
-		return 0, errors.Errorf("given value '%s' is not a number", memory)
+		return 0, err
 	}
-	return uint64(math.Ceil(value * coeficient)), nil
+	return uint64(math.Ceil(value)), nil
 }
 
 // StrToMilliCPU converts CPU as a string representation to millicpus represented as an integer.
+// The result is rounded to the nearest millicpu.
 func StrToMilliCPU(cpu string) (uint64, error) {
 	if cpu == "" {
 		return 0, nil
 	}
+	// Millicpus are exact integers, parse them without float multiplication.
 	if strings.HasSuffix(cpu, "m") {
-		cpu = cpu[:len(cpu)-1]
-		millis, err := strconv.ParseUint(cpu, 10, 64)
+		millis, err := strconv.ParseUint(cpu[:len(cpu)-1], 10, 64)
 		if err != nil {
-			return 0, err
+			return 0, errors.Errorf("given value '%s' is not a whole number of millicpus", cpu)
 		}
 		return millis, nil
 	}
-	floatCPU, err := strconv.ParseFloat(cpu, 64)
+	value, err := parseQuantity(cpu)
 	if err != nil {
 		return 0, err
 	}
-	return uint64(floatCPU * 1000), nil
+	return uint64(math.Round(value * 1000)), nil
 }
 
-// BytesToStr converts integer of bytes to string.
-func BytesToStr(i int64) string {
-	return strconv.FormatInt(i, 10)
+// byteUnits lists units used by BytesToStr from the largest to the smallest.
+var byteUnits = []struct { //nolint:gochecknoglobals
+	suffix string
+	size   uint64
+}{
+	{"Ei", exbiByte}, {"E", exaByte},
+	{"Pi", pebiByte}, {"P", petaByte},
+	{"Ti", tebiByte}, {"T", teraByte},
+	{"Gi", gibiByte}, {"G", gigaByte},
+	{"Mi", mibiByte}, {"M", megaByte},
+	{"Ki", kibiByte}, {"k", kiloByte},
 }
 
-// MilliCPUToStr converts integer of milli CPU to string.
-func MilliCPUToStr(i int32) string {
-	return strconv.FormatInt(int64(i), 10) + "m"
+// BytesToStr converts integer of bytes to string using the largest binary or decimal SI suffix
+// which represents the value exactly, e.g. 1073741824 is converted to "1Gi" and 300000000 to "300M".
+func BytesToStr(i uint64) string {
+	if i == 0 {
+		return "0"
+	}
+	for _, unit := range byteUnits {
+		if i%unit.size == 0 {
+			return strconv.FormatUint(i/unit.size, 10) + unit.suffix
+		}
+	}
+	return strconv.FormatUint(i, 10)
+}
+
+// MilliCPUToStr converts integer of milli CPU to string, whole CPUs are converted without suffix.
+func MilliCPUToStr(i uint64) string {
+	if i%1000 == 0 {
+		return strconv.FormatUint(i/1000, 10)
+	}
+	return strconv.FormatUint(i, 10) + "m"
 }
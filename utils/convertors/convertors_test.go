@@ -40,6 +40,18 @@ func TestConvertToCPUMillis(t *testing.T) {
 		{in: ".", expectedOut: 0, errShouldBeNil: false},
 		{in: "", expectedOut: 0, errShouldBeNil: true},
 		{in: "adf", expectedOut: 0, errShouldBeNil: false},
+		{in: "250m", expectedOut: 250, errShouldBeNil: true},
+		{in: "1.5m", expectedOut: 0, errShouldBeNil: false},
+		{in: "m", expectedOut: 0, errShouldBeNil: false},
+		{in: "1k", expectedOut: 1000 * 1000, errShouldBeNil: true},
+		{in: "1Ki", expectedOut: 1024 * 1000, errShouldBeNil: true},
+		{in: "0.001k", expectedOut: 1000, errShouldBeNil: true},
+		{in: "1M", expectedOut: 1000 * 1000 * 1000, errShouldBeNil: true},
+		{in: "1e3", expectedOut: 1000 * 1000, errShouldBeNil: true},
+		{in: "0.29", expectedOut: 290, errShouldBeNil: true},
+		{in: "-1", expectedOut: 0, errShouldBeNil: false},
+		{in: "1x", expectedOut: 0, errShouldBeNil: false},
+		{in: "1 ", expectedOut: 0, errShouldBeNil: false},
 	}
 
 	for _, test := range testCases {
@@ -79,6 +91,15 @@ func TestConvertToBytes(t *testing.T) {
 		{in: "1Pi", expectedOut: 1024 * 1024 * 1024 * 1024 * 1024, errShouldBeNil: true},
 		{in: "1E", expectedOut: 1000 * 1000 * 1000 * 1000 * 1000 * 1000, errShouldBeNil: true},
 		{in: "1Ei", expectedOut: 1024 * 1024 * 1024 * 1024 * 1024 * 1024, errShouldBeNil: true},
+		{in: "1.5Gi", expectedOut: 1536 * 1024 * 1024, errShouldBeNil: true},
+		{in: "1.5G", expectedOut: 1500 * 1000 * 1000, errShouldBeNil: true},
+		{in: "1m", expectedOut: 1, errShouldBeNil: true},
+		{in: "1e3", expectedOut: 1000, errShouldBeNil: true},
+		{in: "2Mi", expectedOut: 2 * 1024 * 1024, errShouldBeNil: true},
+		{in: "-1Gi", expectedOut: 0, errShouldBeNil: false},
+		{in: "1gi", expectedOut: 0, errShouldBeNil: false},
+		{in: "1KB", expectedOut: 0, errShouldBeNil: false},
+		{in: "1Mi5", expectedOut: 0, errShouldBeNil: false},
 	}
 
 	for _, test := range testCases {
@@ -91,3 +112,54 @@ func TestConvertToBytes(t *testing.T) {
 		)
 	}
 }
+
+func TestBytesToStr(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		in          uint64
+		expectedOut string
+	}{
+		{in: 0, expectedOut: "0"},
+		{in: 1, expectedOut: "1"},
+		{in: 1000, expectedOut: "1k"},
+		{in: 1024, expectedOut: "1Ki"},
+		{in: 300 * 1000 * 1000, expectedOut: "300M"},
+		{in: 350 * 1024 * 1024, expectedOut: "350Mi"},
+		{in: 1000 * 1000 * 1000, expectedOut: "1G"},
+		{in: 1024 * 1024 * 1024, expectedOut: "1Gi"},
+		{in: 1536 * 1024 * 1024, expectedOut: "1536Mi"},
+		{in: 25 * 1000 * 1000 * 1000, expectedOut: "25G"},
+		{in: 16 * 1024 * 1024 * 1024 * 1024, expectedOut: "16Ti"},
+		{in: 1000001, expectedOut: "1000001"},
+	}
+
+	for _, test := range testCases {
+		out := BytesToStr(test.in)
+		assert.Equal(t, test.expectedOut, out, "in=%v", test.in)
+		bytes, err := StrToBytes(out)
+		assert.NoError(t, err)
+		assert.Equal(t, test.in, bytes, "round trip of %v", test.in)
+	}
+}
+
+func TestMilliCPUToStr(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		in          uint64
+		expectedOut string
+	}{
+		{in: 0, expectedOut: "0"},
+		{in: 250, expectedOut: "250m"},
+		{in: 1000, expectedOut: "1"},
+		{in: 1500, expectedOut: "1500m"},
+		{in: 4000, expectedOut: "4"},
+	}
+
+	for _, test := range testCases {
+		out := MilliCPUToStr(test.in)
+		assert.Equal(t, test.expectedOut, out, "in=%v", test.in)
+		millis, err := StrToMilliCPU(out)
+		assert.NoError(t, err)
+		assert.Equal(t, test.in, millis, "round trip of %v", test.in)
+	}
+}
@@ -36,6 +36,13 @@ func Get(ctx context.Context) Logger {
 	return v.(Logger)
 }
 
+// FromContext returns logger from given context produced by GetCtxWithLogger
+// and reports whether it was present.
+func FromContext(ctx context.Context) (Logger, bool) {
+	l, ok := ctx.Value(key).(Logger)
+	return l, ok
+}
+
 // GetCtxWithLogger returns derived context with given logger set.
 // If logger is already present, it will be shadowed.
 func GetCtxWithLogger(ctx context.Context, l Logger) context.Context {
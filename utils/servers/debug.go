@@ -19,6 +19,7 @@ package servers
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	_ "expvar" // register /debug/vars
 	"fmt"
 	"log"
@@ -30,6 +31,7 @@ import (
 	"text/template"
 	"time"
 
+	"github.com/percona/pmm/version"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 
@@ -102,10 +104,13 @@ func RunDebugServer(ctx context.Context, opts *RunDebugServerOpts) {
 	})
 	http.Handle("/debug/metrics", promhttp.InstrumentMetricHandler(prometheus.DefaultRegisterer, metricsHandler))
 
+	http.Handle("/debug/version", http.HandlerFunc(versionHandler))
+
 	handlers := []string{
 		"/debug/healthz",  // by healthzHandler above
 		"/debug/readyz",   // by readyzHandler above
 		"/debug/metrics",  // by metricsHandler above
+		"/debug/version",  // by versionHandler
 		"/debug/vars",     // by expvar
 		"/debug/requests", // by golang.org/x/net/trace imported by google.golang.org/grpc
 		"/debug/events",   // by golang.org/x/net/trace imported by google.golang.org/grpc
@@ -179,3 +184,22 @@ func RunDebugServer(ctx context.Context, opts *RunDebugServerOpts) {
 	<-stopped
 	l.Info("Server stopped.")
 }
+
+// VersionInfo contains build information of running dbaas-controller.
+type VersionInfo struct {
+	Version    string `json:"version"`
+	FullCommit string `json:"full_commit"`
+	Branch     string `json:"branch"`
+	Timestamp  string `json:"timestamp"`
+}
+
+// versionHandler returns VersionInfo as JSON, so clients can check compatibility with running controller.
+func versionHandler(rw http.ResponseWriter, req *http.Request) {
+	rw.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(rw).Encode(VersionInfo{ //nolint:errcheck,gosec
+		Version:    version.Version,
+		FullCommit: version.FullCommit,
+		Branch:     version.Branch,
+		Timestamp:  version.Timestamp,
+	})
+}
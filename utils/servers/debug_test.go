@@ -18,8 +18,13 @@ package servers
 
 import (
 	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
+	"github.com/percona/pmm/version"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -32,4 +37,16 @@ func TestDebugServer(t *testing.T) {
 			})
 		})
 	})
+
+	t.Run("Version", func(t *testing.T) {
+		t.Parallel()
+		rec := httptest.NewRecorder()
+		versionHandler(rec, httptest.NewRequest(http.MethodGet, "/debug/version", nil))
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+		var info VersionInfo
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &info))
+		assert.Equal(t, version.Version, info.Version)
+		assert.Equal(t, version.FullCommit, info.FullCommit)
+	})
 }
@@ -24,6 +24,7 @@ import (
 	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
 	grpc_validator "github.com/grpc-ecosystem/go-grpc-middleware/validator"
 	grpc_prometheus "github.com/grpc-ecosystem/go-grpc-prometheus"
+	"github.com/pkg/errors"
 	"google.golang.org/grpc"
 	channelz "google.golang.org/grpc/channelz/service"
 
@@ -129,10 +130,15 @@ func (s *grpcServer) Run(ctx context.Context) {
 
 	<-ctx.Done()
 
-	// try to stop server gracefully, then not
+	// try to stop server gracefully, then not:
+	// new requests are rejected, in-flight ones have shutdownTimeout to finish.
+	s.l.Infof("Waiting up to %s for in-flight requests to finish...", s.shutdownTimeout)
 	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), s.shutdownTimeout)
 	go func() {
 		<-shutdownCtx.Done()
+		if errors.Is(shutdownCtx.Err(), context.DeadlineExceeded) {
+			s.l.Warnf("In-flight requests haven't finished in %s, stopping forcibly.", s.shutdownTimeout)
+		}
 		s.grpc.Stop()
 	}()
 	s.grpc.GracefulStop()
@@ -0,0 +1,125 @@
+// dbaas-controller
+// Copyright (C) 2020 Percona LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package servers
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// registerSlowService registers a test service with a single unary method
+// that blocks until release is closed.
+func registerSlowService(s *grpc.Server, started chan<- struct{}, release <-chan struct{}) {
+	s.RegisterService(&grpc.ServiceDesc{
+		ServiceName: "servers.test.Slow",
+		HandlerType: (*interface{})(nil),
+		Methods: []grpc.MethodDesc{{
+			MethodName: "Wait",
+			Handler: func(_ interface{}, ctx context.Context, dec func(interface{}) error, _ grpc.UnaryServerInterceptor) (interface{}, error) {
+				if err := dec(new(emptypb.Empty)); err != nil {
+					return nil, err
+				}
+				close(started)
+				<-release
+				return new(emptypb.Empty), nil
+			},
+		}},
+	}, struct{}{})
+}
+
+func freeAddr(t *testing.T) string {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := l.Addr().String()
+	require.NoError(t, l.Close())
+	return addr
+}
+
+// runShutdown starts the server, issues a slow request, cancels the server context
+// once the request is in flight and returns the time Run took to return and the request error.
+func runShutdown(t *testing.T, shutdownTimeout time.Duration, release <-chan struct{}) (time.Duration, error) {
+	t.Helper()
+
+	addr := freeAddr(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	server := NewGRPCServer(ctx, &NewGRPCServerOpts{Addr: addr, ShutdownTimeout: shutdownTimeout})
+	started := make(chan struct{})
+	registerSlowService(server.GetUnderlyingServer(), started, release)
+
+	runDone := make(chan struct{})
+	go func() {
+		defer close(runDone)
+		server.Run(ctx)
+	}()
+
+	conn, err := grpc.Dial(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close() //nolint:errcheck
+
+	reqErr := make(chan error, 1)
+	go func() {
+		reqErr <- conn.Invoke(context.Background(), "/servers.test.Slow/Wait", new(emptypb.Empty), new(emptypb.Empty), grpc.WaitForReady(true))
+	}()
+
+	select {
+	case <-started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("request hasn't reached the server")
+	}
+
+	start := time.Now()
+	cancel()
+	select {
+	case <-runDone:
+	case <-time.After(10 * time.Second):
+		t.Fatal("Run hasn't returned")
+	}
+
+	return time.Since(start), <-reqErr
+}
+
+func TestGRPCServerShutdown(t *testing.T) {
+	t.Run("InFlightRequestFinishes", func(t *testing.T) {
+		release := make(chan struct{})
+		time.AfterFunc(200*time.Millisecond, func() { close(release) })
+
+		_, err := runShutdown(t, 5*time.Second, release)
+		assert.NoError(t, err)
+	})
+
+	t.Run("ForcedAfterTimeout", func(t *testing.T) {
+		release := make(chan struct{})
+		defer close(release)
+
+		elapsed, err := runShutdown(t, 200*time.Millisecond, release)
+		assert.Equal(t, codes.Unavailable, status.Code(err), "%v", err)
+		assert.Less(t, elapsed, 5*time.Second)
+	})
+}